@@ -0,0 +1,82 @@
+package object
+
+import (
+	"reflect"
+	"strings"
+)
+
+// keyAlias returns the target key of the source map key at sourceKey,
+// translated by KeyAliases. Aliases of the full source path, written
+// with dots and without indexes (e.g. "users.full_name"), take precedence
+// over aliases of the bare key.
+func (a *assigner) keyAlias(sourceKey metaKey, key string) (string, bool) {
+	if len(a.config.KeyAliases) == 0 {
+		return "", false
+	}
+
+	alias, ok := a.config.KeyAliases[aliasPath(sourceKey, key)]
+	if !ok {
+		alias, ok = a.config.KeyAliases[key]
+	}
+	if !ok {
+		return "", false
+	}
+
+	// Aliases rename keys in place, the parent of a dotted alias is
+	// the parent of the source key.
+	if i := strings.LastIndexByte(alias, '.'); i >= 0 {
+		alias = alias[i+1:]
+	}
+	return alias, true
+}
+
+// aliasedKeys returns the keys of the source map sourceVal that have an
+// alias, indexed by alias.
+func (a *assigner) aliasedKeys(sourceVal reflect.Value, sourceKey metaKey) map[string]reflect.Value {
+	aliased := make(map[string]reflect.Value)
+	for _, k := range sortedMapKeys(sourceVal) {
+		if alias, ok := a.keyAlias(sourceKey, mapKeyString(k)); ok {
+			if _, exist := aliased[alias]; !exist {
+				aliased[alias] = k
+			}
+		}
+	}
+	return aliased
+}
+
+// renameField renames field, a field of the struct at structKey, to the
+// key given by FieldNames for its path or, failing that, for its name.
+func (a *assigner) renameField(field *fieldInfo, structKey metaKey) {
+	if len(a.config.FieldNames) == 0 {
+		return
+	}
+
+	name, ok := a.config.FieldNames[aliasPath(structKey, field.displayName)]
+	if !ok {
+		name, ok = a.config.FieldNames[field.displayName]
+	}
+	if ok {
+		field.actualName = name
+		field.actualNameVal = reflect.Value{}
+	}
+}
+
+// aliasPath returns the dotted path of key below sourceKey, without
+// slice and array indexes.
+func aliasPath(sourceKey metaKey, key string) string {
+	segments, err := SplitPath(sourceKey.String())
+	if err != nil {
+		return key
+	}
+
+	var path strings.Builder
+	for _, seg := range segments {
+		if seg.Kind == PathIndex {
+			continue
+		}
+		path.WriteString(seg.Name)
+		path.WriteByte('.')
+	}
+	path.WriteString(key)
+	return path.String()
+}
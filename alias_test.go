@@ -0,0 +1,129 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssign_KeyAliases(t *testing.T) {
+	t.Parallel()
+
+	type User struct {
+		Name  string
+		Email string
+	}
+	type Payload struct {
+		Owner User
+		Users []User
+	}
+
+	input := map[string]any{
+		"owner": map[string]any{"full_name": "Ann", "mail": "ann@example.com"},
+		"users": []any{
+			map[string]any{"full_name": "Bob", "mail": "bob@example.com"},
+		},
+	}
+
+	var payload Payload
+	err := Assign(&payload, input, func(c *AssignConfig) {
+		c.KeyAliases = map[string]string{
+			"full_name":  "name",
+			"owner.mail": "owner.email",
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Payload{
+		Owner: User{Name: "Ann", Email: "ann@example.com"},
+		Users: []User{{Name: "Bob"}},
+	}
+	if !reflect.DeepEqual(payload, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, payload)
+	}
+
+	// Indexes are left out of alias paths.
+	payload = Payload{}
+	err = Assign(&payload, input, func(c *AssignConfig) {
+		c.KeyAliases = map[string]string{"users.mail": "email"}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if payload.Users[0].Email != "bob@example.com" || payload.Owner.Email != "" {
+		t.Fatalf("bad: %+v", payload)
+	}
+
+	// Map targets are keyed by alias.
+	var m map[string]any
+	err = Assign(&m, map[string]any{"a": 1, "b": 2}, func(c *AssignConfig) {
+		c.KeyAliases = map[string]string{"a": "x"}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(m, map[string]any{"x": 1, "b": 2}) {
+		t.Fatalf("bad: %v", m)
+	}
+}
+
+func TestAssign_FieldNames(t *testing.T) {
+	t.Parallel()
+
+	type Server struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+	type Config struct {
+		Name   string
+		Server Server
+		Backup Server
+	}
+
+	fieldNames := func(c *AssignConfig) {
+		c.FieldNames = map[string]string{
+			"Name":        "service_name",
+			"Server.Port": "listen_port",
+			"Host":        "hostname",
+		}
+	}
+
+	source := map[string]any{
+		"service_name": "api",
+		"server":       map[string]any{"hostname": "a", "listen_port": 80, "port": 1},
+		"backup":       map[string]any{"hostname": "b", "port": 81},
+	}
+
+	var md Metadata
+	var result Config
+	if err := Assign(&result, source, fieldNames, func(c *AssignConfig) {
+		c.Metadata = &md
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Config{
+		Name:   "api",
+		Server: Server{Host: "a", Port: 80},
+		Backup: Server{Host: "b", Port: 81},
+	}
+	if result != expected {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+	if !reflect.DeepEqual(md.Unused, []string{"server[port]"}) {
+		t.Fatalf("expected the tagged key to be unused, got %v", md.Unused)
+	}
+
+	// Structs assigned to maps are renamed alike
+	var generic map[string]any
+	if err := Assign(&generic, expected, fieldNames, func(c *AssignConfig) {
+		c.DeepInterfaceMaps = true
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	server, _ := generic["server"].(map[string]any)
+	if generic["service_name"] != "api" || server["hostname"] != "a" || server["listen_port"] != 80 {
+		t.Fatalf("unexpected result %v", generic)
+	}
+}
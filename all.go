@@ -0,0 +1,21 @@
+//go:build go1.23
+
+package object
+
+import (
+	"iter"
+	"reflect"
+)
+
+// All returns an iterator over the leaves of v, yielding the path of each
+// leaf (e.g. "server.ports[0]", see Document) with its value. Maps are
+// visited in key order, structs are visited through their keys as Assign
+// would produce them and empty maps, slices and arrays are leaves. Values
+// are visited lazily, so breaking out of the loop stops the traversal.
+//
+// All requires Go 1.23.
+func All(v any) iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		PathFormat.walkLeaves(reflect.ValueOf(v), "", yield)
+	}
+}
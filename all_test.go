@@ -0,0 +1,75 @@
+//go:build go1.23
+
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+
+	type Server struct {
+		Host  string `json:"host"`
+		Ports []int  `json:"ports"`
+	}
+
+	type Config struct {
+		Server Server         `json:"server"`
+		Labels map[string]any `json:"labels"`
+		Empty  []string       `json:"empty"`
+	}
+
+	input := Config{
+		Server: Server{Host: "localhost", Ports: []int{80, 443}},
+		Labels: map[string]any{"a.b": "dotted", "env": "prod"},
+		Empty:  []string{},
+	}
+
+	var paths []string
+	values := map[string]any{}
+	for path, value := range All(&input) {
+		paths = append(paths, path)
+		values[path] = value
+	}
+
+	expectedPaths := []string{"empty", "labels[a.b]", "labels.env", "server.host", "server.ports[0]", "server.ports[1]"}
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Fatalf("bad paths: %#v", paths)
+	}
+	if values["server.ports[1]"] != 443 || values["labels[a.b]"] != "dotted" {
+		t.Fatalf("bad values: %#v", values)
+	}
+
+	// Every path resolves in a Document built from the same data
+	var doc Document
+	if err := Assign(&doc, input, func(c *AssignConfig) {
+		c.DeepInterfaceMaps = true
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, path := range paths {
+		if !doc.Has(path) {
+			t.Fatalf("path %q not found in document", path)
+		}
+	}
+}
+
+func TestAll_Break(t *testing.T) {
+	t.Parallel()
+
+	input := []any{1, 2, 3, 4}
+
+	count := 0
+	for path := range All(input) {
+		count++
+		if path == "[1]" {
+			break
+		}
+	}
+
+	if count != 2 {
+		t.Fatalf("expected traversal to stop after 2 leaves, got %d", count)
+	}
+}
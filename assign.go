@@ -1,28 +1,25 @@
 package object
 
 import (
+	"encoding"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 var defaultAssigner *assigner
-var weakAssigner *assigner
 
 func init() {
 	defaultAssigner = newAssigner(&AssignConfig{
 		TagName:   "json",
 		Converter: toLowerCamel,
 	})
-	weakAssigner = newAssigner(&AssignConfig{
-		TagName:          "json",
-		Converter:        toLowerCamel,
-		WeaklyTypedInput: true,
-	})
 }
 
 // AssignConfig is the configuration used to create a new decoder
@@ -36,8 +33,8 @@ type AssignConfig struct {
 	//   - bools to int/uint (true = 1, false = 0)
 	//   - strings to int/uint (base implied by prefix)
 	//   - int to bool (true if value != 0)
-	//   - string to bool (accepts: 1, t, T, TRUE, true, True, 0, f, F,
-	//     FALSE, false, False. Anything else is an error)
+	//   - string to bool (accepts: 1, t, true, 0, f, false in any case,
+	//     or the TrueStrings/FalseStrings vocabulary. Anything else is an error)
 	//   - empty array = empty map and vice versa
 	//   - negative numbers to overflowed uint values (base 10)
 	//   - slice of maps to a merged map
@@ -51,24 +48,414 @@ type AssignConfig struct {
 	// This defaults to "json"
 	TagName string
 
+	// TagNames are fallback tag names, read in order for fields without
+	// a TagName tag. For example TagName "yaml" with TagNames ["json"]
+	// honors json tags on structs that don't have yaml ones.
+	TagNames []string
+
 	// IncludeIgnoreFields includes all struct fields that were ignored by '-'
 	IncludeIgnoreFields bool
 
+	// IgnoreUntaggedFields if true leaves out the struct fields without a
+	// TagName (or TagNames) tag when structs are assigned to maps, as if
+	// they were tagged '-'.
+	IgnoreUntaggedFields bool
+
+	// ZeroFields if true replaces target maps and slices with new ones
+	// instead of merging the source into them.
+	ZeroFields bool
+
 	// Converter is the function used to convert the struct field name
 	// to map key. Defaults to `Lower Camel`.
 	Converter func(fieldName string) string
 
+	// Initialisms are words, such as "ID" or "URL", that the default
+	// Converter writes in upper case, e.g. "UserID" becomes "userID"
+	// instead of "userId". See DefaultInitialisms. They are ignored by
+	// other Converters.
+	Initialisms []string
+
 	// Metadata is the struct that will contain extra metadata about
 	// the decoding. If this is nil, then no metadata will be tracked.
 	Metadata *Metadata
 
+	// SortMetadata if true sorts the Keys, Unused, Unset and Warnings
+	// recorded in Metadata by each call to Assign by path, with indexes
+	// compared by value, so they don't depend on map iteration order.
+	// Entries recorded by earlier calls keep their position.
+	SortMetadata bool
+
+	// KeyStringification selects how non string map keys are converted
+	// to string keys. Defaults to KeyStringWeak.
+	KeyStringification KeyStringPolicy
+
+	// KeyAliases translates source map keys to target keys, for types
+	// that can't be tagged. Keys are either bare keys, translated at any
+	// depth, or dotted source paths without indexes (e.g.
+	// "users.full_name"), which take precedence. Aliases rename keys, they
+	// don't move values to another parent.
+	KeyAliases map[string]string
+
+	// FieldNames renames struct fields, for types that can't be tagged. It
+	// maps the path of a field, made of Go field names without indexes
+	// (e.g. "Server.Port"), or a bare field name, to the key of the field,
+	// which takes precedence over its tag. Paths take precedence over bare
+	// names. Fields are renamed when structs are assigned from and to maps.
+	FieldNames map[string]string
+
+	// GroupKeys if true splits the dotted keys of struct fields, such as
+	// `json:"db.host"`, into nested maps when structs are assigned to maps
+	// of interfaces, e.g. {"db": {"host": ...}}. Fields sharing a prefix
+	// are grouped into the same map.
+	GroupKeys bool
+
+	// FailFast stops at the first error instead of collecting the errors
+	// of every field and element. The target is left partially assigned.
+	FailFast bool
+
+	// ErrorUnused returns an UnusedKeyError when a source map has keys
+	// that don't match any field of the target struct.
+	ErrorUnused bool
+
 	// SkipKeys is a list of keys that should be skipped during decoding.
 	SkipKeys []string
 
 	// SkipSameValues if true will skip the same values during decoding.
 	SkipSameValues bool
+
+	// NilSource selects what happens to a target when the source value is
+	// nil, including typed nil pointers, maps and slices. Defaults to
+	// NilSourceDefault.
+	NilSource NilSourcePolicy
+
+	// NilPointers selects how nil pointer fields are emitted when a struct
+	// is assigned to a map. Defaults to NilPointerTyped.
+	NilPointers NilPointerPolicy
+
+	// JSONMarshalers selects how source values implementing json.Marshaler
+	// are assigned to map, interface and string targets, including the
+	// values of maps produced from structs. By default they are assigned
+	// through reflection like any other value.
+	JSONMarshalers JSONMarshalerMode
+
+	// UseJSONInterfaces if true honors the json.Unmarshaler implementation
+	// of targets, which decode the JSON encoding of the source, and the
+	// json.Marshaler implementation of sources, whose MarshalJSON output
+	// is parsed and assigned unless JSONMarshalers selects otherwise. Types
+	// with custom JSON behavior then round-trip as with encoding/json.
+	UseJSONInterfaces bool
+
+	// TimeLayout if set is the layout time.Time values are formatted with
+	// when structs are assigned to maps, including with DeepInterfaceMaps.
+	// By default time.Time values are stored as is, and formatted as
+	// RFC 3339 strings into maps of strings.
+	TimeLayout string
+
+	// PromoteUnexportedEmbedded if true will promote the exported fields of
+	// unexported embedded structs (e.g. `type T struct{ base }`), like
+	// encoding/json does. Nil unexported embedded pointers can't be
+	// allocated and are left untouched in targets.
+	PromoteUnexportedEmbedded bool
+
+	// UseGetters if true will call the GetFieldName() method of a source
+	// struct when it has no exported field matching a target field, which
+	// is common with protobuf generated types.
+	UseGetters bool
+
+	// BoolStrings if true will convert strings to bools like
+	// WeaklyTypedInput does, without enabling the other weak conversions.
+	BoolStrings bool
+
+	// TrueStrings and FalseStrings, when set, replace the vocabulary used
+	// to convert strings to bools (e.g. "yes", "on"). Words are matched
+	// case-insensitively and surrounding whitespace is ignored.
+	TrueStrings  []string
+	FalseStrings []string
+
+	// SafeNumerics if true will reject every implicit numeric narrowing.
+	// Integer targets only accept values within their range (and floats
+	// only when they are integral), unsigned targets reject negative values
+	// and float targets only accept values they can represent exactly.
+	// Violations are reported as *RangeError.
+	SafeNumerics bool
+
+	// NumberStrings if true will convert between numbers and their decimal
+	// text losslessly, even without WeaklyTypedInput. Numeric sources are
+	// written to string targets using the shortest text that round-trips
+	// (json.Number keeps its original text), decimal strings are parsed
+	// into numeric targets, and strings assigned to json.Number targets
+	// must be valid numbers. Useful for money-like fields where a float64
+	// round-trip is unacceptable.
+	NumberStrings bool
+
+	// CompactSlices if true will remove the zero value elements from slice
+	// targets after assignment.
+	CompactSlices bool
+
+	// DedupeSlices if true will remove duplicate elements from slice
+	// targets after assignment, keeping the first occurrence.
+	DedupeSlices bool
+
+	// SortSlices if true will sort slice targets of ordered element types
+	// (integers, floats and strings) in ascending order after assignment.
+	SortSlices bool
+
+	// IntegerFormats restricts the prefixed integer formats accepted when
+	// weakly parsing strings into integers. By default hex ("0x"), octal
+	// ("0o" or a leading "0") and binary ("0b") strings are all accepted.
+	// Once set, only decimal strings and the listed formats are, e.g.
+	// IntegerDecimal | IntegerHex. Leading zeros are then read as decimal
+	// unless IntegerOctal is listed.
+	IntegerFormats IntegerFormat
+
+	// IntegerExponents if true accepts strings and json.Numbers written in
+	// scientific notation, such as "1e3", for integer targets when their
+	// value is integral. Other values, such as "1.5e0", fail with an error.
+	IntegerExponents bool
+
+	// TruncateArrays if true will fill array targets with the first
+	// elements of longer sources instead of failing, the number of dropped
+	// elements is recorded in Metadata.Warnings.
+	TruncateArrays bool
+
+	// CopyBytes if true will copy byte slices that would otherwise be
+	// assigned by reference, such as []byte values stored in interface
+	// targets or in maps built from structs, so that mutating the source
+	// afterwards doesn't change the target and vice versa.
+	CopyBytes bool
+
+	// DeepInterfaceMaps if true will convert nested structs into
+	// map[string]any when assigning structs to maps with interface values,
+	// at any depth and including structs held by pointers, slices, arrays
+	// and maps, so the output only holds generic values. By default nested
+	// structs are stored as is.
+	DeepInterfaceMaps bool
+
+	// Squash selects which struct fields are squashed, their fields being
+	// promoted into the parent as if they were declared there. By default
+	// embedded structs and fields tagged "squash" are. The "nosquash" tag
+	// option keeps an embedded struct as a nested key.
+	Squash SquashMode
+
+	// SquashCollisions selects how keys defined by several embedded or
+	// squashed structs at the same depth are handled. By default the first
+	// field found wins, SquashCollisionError reports them as an error.
+	SquashCollisions SquashCollisionPolicy
+
+	// UnsupportedSources selects how source values of kind chan, func and
+	// unsafe pointer are handled. By default they fail the assignment
+	// unless the target can hold them, UnsupportedSourceSkip skips them.
+	UnsupportedSources UnsupportedSourcePolicy
+
+	// MultiValues selects how string slices are assigned to scalar
+	// targets, e.g. when binding url.Values or http.Header to a struct.
+	// Defaults to MultiValueNone.
+	MultiValues MultiValueMode
+
+	// MultiValueSeparator separates the values joined by MultiValueJoin.
+	MultiValueSeparator string
+
+	// Wrappers if true assigns wrapper types, such as the wrapperspb types
+	// of protobuf, as the scalar they wrap: scalars are assigned to their
+	// Value field and wrappers are unwrapped when assigned to other types.
+	Wrappers bool
+
+	// Metrics if set observes every call to Assign, see Metrics.
+	Metrics Metrics
+
+	// Hook if set is called with every non nil source value before it is
+	// assigned, and the value it returns is assigned instead. Errors it
+	// returns are attached to the path of the value, see NewFieldError.
+	Hook HookFunc
+
+	// SourceKeyNormalizer if set is applied to the keys of source maps and
+	// to the key names of target fields when a field has no exact match,
+	// so that e.g. "max-conns", "max_conns" and "maxConns" all match the
+	// same field. It is independent of the Converter.
+	SourceKeyNormalizer func(key string) string
+
+	// PluralizeKeys if true matches struct fields without an exact match
+	// with the singular or plural variant of their key, e.g. "host" for
+	// the key "hosts" and "hosts" for "host". Single values are wrapped
+	// into a slice for slice fields, and scalar fields take the first
+	// element of slices. It is tried before MatchName.
+	PluralizeKeys bool
+
+	// MatchName if set reports whether a source map key matches the key
+	// name of a target field, for fields without an exact match (or one
+	// found through SourceKeyNormalizer). It allows e.g. case insensitive
+	// matching with strings.EqualFold. Keys are tried in order and the
+	// first match wins.
+	MatchName func(sourceKey, fieldName string) bool
+
+	// ContextHook is like Hook, but also receives the HookContext of the
+	// value, such as the struct and tag of the field being assigned. It is
+	// called after Hook.
+	ContextHook ContextHookFunc
+
+	// ValueHook is like Hook, but receives the source value and the target
+	// value itself, without converting them to interfaces. It is called
+	// after Hook and ContextHook.
+	ValueHook ValueHookFunc
+
+	// CollectionHook if set is called once with every source slice, array
+	// and map assigned to a slice, array or map target, before its elements
+	// are assigned, so whole collections can be transformed, e.g. filtered
+	// or re-keyed. It is called after Hook and ContextHook, and its errors
+	// are handled alike.
+	CollectionHook HookFunc
+
+	// ReplaceInterfaces if true replaces the values held by interface
+	// targets with the source value, instead of decoding the source into
+	// them. For example a *Basic held by an any field is replaced by the
+	// source map.
+	ReplaceInterfaces bool
+
+	// MergeStrategy selects how source values are merged into target
+	// values that are already set. Defaults to MergeOverwrite.
+	MergeStrategy MergeStrategy
+
+	// SliceMerge selects how source slices are merged into non-empty
+	// target slices and arrays, unless SliceMergeKey is set. Defaults to
+	// SliceMergeTruncate.
+	SliceMerge SliceMergeStrategy
+
+	// MergeInterfaceMaps if true merges source maps and structs into the
+	// maps and structs held by interface typed maps, such as
+	// map[string]any, and source slices into the slices they hold unless
+	// SliceMerge is SliceMergeTruncate. Values of other kinds are replaced.
+	// By default the values of interface typed maps are replaced. Merge
+	// sets it.
+	MergeInterfaceMaps bool
+
+	// SliceMergeKey if set will merge source slices into non-empty target
+	// slices of structs by matching elements on the value of this key (a
+	// field name or its tag name) instead of by index: matching elements
+	// are updated, the others are appended and unmatched target elements
+	// are kept. See SliceMergeByKey.
+	SliceMergeKey string
+
+	// SortKeys if true will sort the entries by key when assigning maps or
+	// structs to []KV targets, producing deterministic output. Otherwise
+	// entries follow the map iteration order.
+	SortKeys bool
+
+	// UnnamedTag selects how fields whose tag has options but no name
+	// (e.g. `json:",omitempty"`) are named. Defaults to UnnamedTagConverter,
+	// which names them like untagged fields.
+	UnnamedTag UnnamedTagPolicy
+
+	// OmitZeroStructs if true will also omit zero-valued struct fields tagged
+	// with omitempty. By default omitempty follows encoding/json: false, 0,
+	// nil pointers and interfaces, and empty arrays, slices, maps and strings
+	// are omitted, while structs are never considered empty.
+	OmitZeroStructs bool
+
+	// EmptyStructAsNil if true will leave a nil map instead of an empty map
+	// when a struct converted to a map yields no entries, both for the
+	// target itself and for nested struct fields converted to maps.
+	EmptyStructAsNil bool
+
+	// Recover if true will convert panics raised while assigning, for
+	// example by Decimal implementations or custom types, into a
+	// *PanicError carrying the path of the value being assigned.
+	Recover bool
+
+	// Cache if set will reuse the results of earlier assignments of
+	// identical sources, see Cache.
+	Cache *Cache
+
+	// ApplyDefaults if true assigns the default tag of struct fields that
+	// have no source value, parsed with weak typing, see DefaultTagName.
+	// Fields that are already set keep their value.
+	ApplyDefaults bool
+
+	// Validate if true checks the rules of the validate tag of struct
+	// fields once the struct is assigned, see ValidateTagName. Violations
+	// are reported as *ValidationError, alongside the other errors.
+	Validate bool
+
+	// MemoryLimit if positive bounds the estimated bytes allocated for new
+	// strings, slices and maps by a single call to Assign. The assignment
+	// is aborted with a *LimitError once the estimate exceeds it, which
+	// protects services decoding untrusted payloads.
+	MemoryLimit int
 }
 
+// IntegerFormat is a set of integer string formats, see
+// AssignConfig.IntegerFormats.
+type IntegerFormat int
+
+const (
+	// IntegerDecimal allows decimal strings, which are always accepted.
+	// On its own it denies every prefixed format.
+	IntegerDecimal IntegerFormat = 1 << iota
+
+	// IntegerHex allows hexadecimal strings such as "0x1F".
+	IntegerHex
+
+	// IntegerOctal allows octal strings such as "0o17" or "017".
+	IntegerOctal
+
+	// IntegerBinary allows binary strings such as "0b101".
+	IntegerBinary
+
+	// IntegerAll allows every format, like the default.
+	IntegerAll = IntegerDecimal | IntegerHex | IntegerOctal | IntegerBinary
+)
+
+// NilSourcePolicy selects how nil source values are assigned.
+type NilSourcePolicy int
+
+const (
+	// NilSourceDefault sets pointer targets to nil when the source is a
+	// typed nil pointer, map or slice, and leaves any other target
+	// untouched. Untyped nil sources leave every target untouched.
+	NilSourceDefault NilSourcePolicy = iota
+
+	// NilSourceKeep leaves the target untouched.
+	NilSourceKeep
+
+	// NilSourceClear sets the target to its zero value, e.g. a nil map,
+	// slice or pointer, whether the source is typed or not.
+	NilSourceClear
+
+	// NilSourceError returns an error.
+	NilSourceError
+)
+
+// NilPointerPolicy selects how nil pointer fields are emitted when a
+// struct is assigned to a map.
+type NilPointerPolicy int
+
+const (
+	// NilPointerTyped emits nil pointer fields as typed nil pointers.
+	NilPointerTyped NilPointerPolicy = iota
+
+	// NilPointerUntyped emits nil pointer fields as untyped nil values
+	// when the map holds interfaces.
+	NilPointerUntyped
+
+	// NilPointerZero emits the zero value of the element type of nil
+	// pointer fields, e.g. 0 for a nil *int.
+	NilPointerZero
+
+	// NilPointerOmit leaves nil pointer fields out of the map.
+	NilPointerOmit
+)
+
+// UnnamedTagPolicy selects the key used for fields whose tag has no name.
+type UnnamedTagPolicy int
+
+const (
+	// UnnamedTagConverter names the field using AssignConfig.Converter,
+	// exactly like fields without a tag.
+	UnnamedTagConverter UnnamedTagPolicy = iota
+
+	// UnnamedTagFieldName names the field using the raw struct field name.
+	UnnamedTagFieldName
+)
+
 // Metadata contains information about the decoding process that
 // would be tedious or difficult to obtain otherwise.
 type Metadata struct {
@@ -83,10 +470,86 @@ type Metadata struct {
 	// but weren't set in the decoding process since there was no matching value
 	// in the input
 	Unset []string
+
+	// UnsetReasons holds the reason each Unset key wasn't set, by key.
+	UnsetReasons map[string]UnsetReason
+
+	// AltKeys holds the source key of the target keys populated from an
+	// "alt=" key of their tag, so that deprecated keys can be reported.
+	AltKeys map[string]string
+
+	// Warnings are the non fatal issues of the decoding process, such as
+	// source elements dropped by TruncateArrays
+	Warnings []string
+}
+
+// sortFrom sorts the entries of m appended since it was copied to prev by
+// path, see AssignConfig.SortMetadata.
+func (m *Metadata) sortFrom(prev Metadata) {
+	for _, entries := range []struct {
+		current []string
+		prev    []string
+	}{
+		{m.Keys, prev.Keys},
+		{m.Unused, prev.Unused},
+		{m.Unset, prev.Unset},
+		{m.Warnings, prev.Warnings},
+	} {
+		added := entries.current[len(entries.prev):]
+		sort.SliceStable(added, func(i, j int) bool {
+			return pathLess(added[i], added[j])
+		})
+	}
+}
+
+// UnsetReason tells why a target key wasn't set, see Metadata.UnsetReasons.
+type UnsetReason int
+
+const (
+	// UnsetMissing is the reason of keys without a source value.
+	UnsetMissing UnsetReason = iota
+
+	// UnsetUnsettable is the reason of fields that can't be set, such as
+	// unexported fields.
+	UnsetUnsettable
+
+	// UnsetSkipped is the reason of keys skipped by the configuration or
+	// the tag options, e.g. SkipKeys, SkipSameValues or "when=".
+	UnsetSkipped
+
+	// UnsetHookNil is the reason of keys whose source value was dropped
+	// by a hook returning nil.
+	UnsetHookNil
+)
+
+func (r UnsetReason) String() string {
+	switch r {
+	case UnsetMissing:
+		return "missing"
+	case UnsetUnsettable:
+		return "unsettable"
+	case UnsetSkipped:
+		return "skipped"
+	case UnsetHookNil:
+		return "hook nil"
+	default:
+		return "unknown"
+	}
 }
 
 // Assign decodes values from the source object and assigns them to the target object.
 // This function uses reflection, so it can handle objects of any type.
+//
+// Every value reachable from target is settable: values that aren't addressable,
+// such as map values and values held by interfaces, are copied, decoded into and
+// stored back. Struct, map, slice and array values of typed maps are merged, as
+// are the values of maps of interfaces with methods, which can't be replaced by
+// decoded values. Other map values, including the values of map[string]any, are
+// replaced unless MergeKeepExisting or MergeInterfaceMaps is used, see Merge.
+//
+// Assign is safe for concurrent use. Sources are only read, so the same source
+// may be assigned to several targets concurrently, but a Metadata must not be
+// shared between concurrent calls.
 // Parameters:
 //   - target: Any type, pointer to the object that will be assigned values.
 //   - source: Any type, source object whose values will be decoded into target.
@@ -99,21 +562,89 @@ func Assign(target any, source any, configs ...func(c *AssignConfig)) error {
 	return defaultAssigner.Assign(target, source, configs...)
 }
 
+// Assigner assigns values with a configuration fixed at construction, so
+// that it is applied once instead of on every call. It is safe for
+// concurrent use, Metadata should therefore be passed per call.
+type Assigner struct {
+	assigner *assigner
+}
+
+// NewAssigner returns an Assigner using the default configuration of
+// Assign modified by configs.
+func NewAssigner(configs ...func(c *AssignConfig)) *Assigner {
+	return &Assigner{assigner: defaultAssigner.withConfig(configs...)}
+}
+
+// Assign works like the package level Assign with the configuration of
+// the Assigner. configs are applied to a copy of that configuration for
+// this call only.
+func (as *Assigner) Assign(target any, source any, configs ...func(c *AssignConfig)) error {
+	return as.assigner.Assign(target, source, configs...)
+}
+
+// assigner holds a configuration and the caches derived from it. It is
+// immutable after construction so it can be shared between goroutines,
+// per call configurations are applied to a copy, see withConfig.
 type assigner struct {
 	config        *AssignConfig
 	skipKeysCache map[string]struct{}
+
+	// keyAssigner converts map keys. It shares the configuration of the
+	// assigner with weak typing forced on and without metadata tracking.
+	keyAssigner *assigner
+
+	// generated is true when generated AssignFrom and AssignTo methods
+	// are preferred over reflection, see usesGenerated.
+	generated bool
+
+	// fastMaps is true when string keyed maps can be copied by
+	// assignMapFast, see usesFastMaps.
+	fastMaps bool
+
+	// budget tracks the allocations of the current call to Assign when
+	// MemoryLimit is set, see withBudget.
+	budget *memoryBudget
+
+	// keys counts the keys assigned by the current call to Assign when
+	// Metrics is set, see assignObserved.
+	keys *int
+
+	// tags caches the parsed tags of struct types when the Converter isn't
+	// the default one, see structTags. It maps a reflect.Type to a
+	// []parsedTag and is shared by concurrent calls.
+	tags *sync.Map
 }
 
 func newAssigner(c *AssignConfig) *assigner {
 	a := &assigner{
 		config:        c,
 		skipKeysCache: make(map[string]struct{}),
+		generated:     usesGenerated(c),
+		fastMaps:      usesFastMaps(c),
+		tags:          &sync.Map{},
 	}
 
 	for _, k := range c.SkipKeys {
 		a.skipKeysCache[k] = struct{}{}
 	}
 
+	keyConfig := *c
+	keyConfig.WeaklyTypedInput = true
+	keyConfig.Metadata = nil
+	keyConfig.SkipKeys = nil
+	keyConfig.Hook = nil
+	keyConfig.ContextHook = nil
+	keyConfig.ValueHook = nil
+	keyConfig.CollectionHook = nil
+	keyConfig.MergeStrategy = MergeOverwrite
+	a.keyAssigner = &assigner{
+		config:        &keyConfig,
+		skipKeysCache: map[string]struct{}{},
+		fastMaps:      usesFastMaps(&keyConfig),
+		tags:          &sync.Map{},
+	}
+	a.keyAssigner.keyAssigner = a.keyAssigner
+
 	return a
 }
 
@@ -134,6 +665,12 @@ func (a *assigner) withConfig(configs ...func(c *AssignConfig)) *assigner {
 		if config.Metadata.Unset == nil {
 			config.Metadata.Unset = []string{}
 		}
+		if config.Metadata.UnsetReasons == nil {
+			config.Metadata.UnsetReasons = map[string]UnsetReason{}
+		}
+		if config.Metadata.AltKeys == nil {
+			config.Metadata.AltKeys = map[string]string{}
+		}
 	}
 
 	return newAssigner(&config)
@@ -164,28 +701,112 @@ func (a *assigner) Assign(target, source any, configs ...func(c *AssignConfig))
 
 	sourceVal := reflect.ValueOf(source)
 
+	if as.config.Metrics != nil {
+		return as.assignObserved(targetVal, sourceVal)
+	}
+
+	return as.assignRoot(targetVal, sourceVal)
+}
+
+// assignRoot assigns sourceVal to the dereferenced target of Assign.
+func (a *assigner) assignRoot(targetVal, sourceVal reflect.Value) error {
+	if a.config.Metadata != nil && a.config.SortMetadata {
+		defer a.config.Metadata.sortFrom(*a.config.Metadata)
+	}
+
+	if a.config.MemoryLimit > 0 {
+		a = a.withBudget()
+	}
+
+	if a.config.Cache != nil {
+		if ok, err := a.assignCached(targetVal, sourceVal); ok {
+			return a.limitError(err)
+		}
+	}
+
 	// Perform the assignment
-	return as.assign(targetVal, "", sourceVal, "")
+	return a.limitError(a.assign(targetVal, "", sourceVal, ""))
 }
 
 // assign decodes an unknown data type into a specific reflection value.
 func (a *assigner) assign(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) error {
+	return a.assignContext(targetVal, targetKey, sourceVal, sourceKey, nil)
+}
+
+// assignContext is assign for the struct field described by field, nil
+// for other values. The field is passed to the ContextHook.
+func (a *assigner) assignContext(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey, field *fieldInfo) (err error) {
+	if a.config.Recover {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{Path: targetKey.String(), Value: r}
+			}
+		}()
+	}
+
 	// Check if we should skip this key based on configuration
 	if a.shouldSkipKey(targetKey, sourceKey) {
 		return nil
 	}
 
-	// Handle typed nil values
-	if sourceVal.IsValid() {
-		// Check if input is a typed nil. Typed nils won't
-		// match the "source == nil" check below, so we handle them here.
-		if sourceVal.Kind() == reflect.Ptr && sourceVal.IsNil() {
-			sourceVal = reflect.Value{}
+	// The assignment is aborted once the memory budget is exhausted
+	if a.spent() {
+		return nil
+	}
+
+	// Unwrap interfaces, nil interfaces become invalid values.
+	if sourceVal.IsValid() && sourceVal.Kind() == reflect.Interface {
+		sourceVal = sourceVal.Elem()
+	}
+
+	// Remember whether there is a source value, hooks may drop it
+	hasSource := sourceVal.IsValid()
+
+	if a.config.Hook != nil && sourceVal.IsValid() {
+		sourceVal, err = a.applyHook(targetVal, targetKey, sourceVal)
+		if err != nil {
+			return err
 		}
 	}
 
-	// Handle invalid source values
-	if !sourceVal.IsValid() {
+	if a.config.ContextHook != nil && sourceVal.IsValid() {
+		sourceVal, err = a.applyContextHook(targetVal, targetKey, sourceVal, field)
+		if err != nil {
+			return err
+		}
+	}
+
+	if a.config.ValueHook != nil && sourceVal.IsValid() {
+		sourceVal, err = a.applyValueHook(targetVal, targetKey, sourceVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	if a.config.CollectionHook != nil && isCollection(sourceVal) && isCollectionKind(targetVal.Kind()) {
+		sourceVal, err = a.applyCollectionHook(targetVal, targetKey, sourceVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Handle nil source values, typed nil pointers, maps and slices
+	// included, according to the NilSource policy.
+	if isNilSource(sourceVal) {
+		if hasSource && !sourceVal.IsValid() && a.config.NilSource != NilSourceClear {
+			a.addMetaUnset(targetKey, UnsetHookNil)
+		}
+		return a.assignNil(targetVal, targetKey, sourceVal)
+	}
+
+	if targetVal.Kind() != reflect.Func && a.skipUnsupported(sourceVal) {
+		a.addMetaUnused(sourceKey)
+		return nil
+	}
+
+	if a.keepsExisting(targetVal) {
+		a.addMetaUnused(sourceKey)
+		a.addMetaUnset(targetKey, UnsetSkipped)
 		return nil
 	}
 
@@ -193,20 +814,105 @@ func (a *assigner) assign(targetVal reflect.Value, targetKey metaKey, sourceVal
 	if a.config.SkipSameValues {
 		if reflect.DeepEqual(targetVal.Interface(), sourceVal.Interface()) {
 			a.addMetaUnused(sourceKey)
-			a.addMetaUnset(targetKey)
+			a.addMetaUnset(targetKey, UnsetSkipped)
 			return nil
 		}
 	}
 
-	if sourceVal.Kind() == reflect.Interface {
-		sourceVal = sourceVal.Elem()
+	if a.generated {
+		if ok, err := a.assignGenerated(targetVal, sourceVal); ok {
+			if err != nil && targetKey != "" {
+				// Generated code reports paths relative to its own
+				// value, the assignment is repeated with reflection to
+				// report the full paths
+				return a.withoutGenerated().assign(targetVal, targetKey, sourceVal, sourceKey)
+			}
+			return err
+		}
+	}
+
+	if targetVal.Type() == rawMessageType {
+		err = a.assignRawMessage(targetVal, targetKey, sourceVal)
+		if err == nil {
+			a.addMetaKey(targetKey)
+		}
+		return err
+	}
+
+	if a.config.UseJSONInterfaces {
+		if ok, err := a.assignJSONUnmarshaler(targetVal, targetKey, sourceVal); ok {
+			if err == nil {
+				a.addMetaKey(targetKey)
+			}
+			return err
+		}
 	}
 
 	// Process based on target type
-	var err error
 	targetKind := targetVal.Kind()
 	addMetaKey := true
 
+	if source := indirectValue(sourceVal); a.config.Wrappers && source.IsValid() && source.Type() != targetVal.Type() {
+		if value, ok := a.unwrapValue(sourceVal); ok {
+			sourceVal = value
+		}
+		if value, ok := a.wrappedTarget(targetVal, sourceVal); ok {
+			return a.assign(value, targetKey, sourceVal, sourceKey)
+		}
+	}
+
+	if value, ok := a.multiValue(targetKind, sourceVal); ok {
+		if !value.IsValid() {
+			a.addMetaUnused(sourceKey)
+			return nil
+		}
+		sourceVal = value
+	}
+
+	if dec, ok := decimalTarget(targetVal); ok {
+		err = a.assignDecimal(dec, targetVal, targetKey, sourceVal)
+		if err == nil {
+			a.addMetaKey(targetKey)
+		}
+		return err
+	}
+
+	if flags, ok := lookupFlags(targetVal.Type()); ok {
+		if ok, err := a.assignFlags(flags, targetVal, targetKey, sourceVal); ok {
+			if err == nil {
+				a.addMetaKey(targetKey)
+			}
+			return err
+		}
+	}
+
+	if marshaled, ok, err := a.marshalJSONSource(targetKind, targetKey, sourceVal); err != nil {
+		return err
+	} else if ok {
+		if !marshaled.IsValid() {
+			return a.assignNil(targetVal, targetKey, marshaled)
+		}
+		sourceVal = marshaled
+	}
+
+	// Bit flags are assigned to slices as the names of their bits
+	if isArraySlice(targetKind) {
+		if names, ok := flagNames(sourceVal); ok {
+			sourceVal = names
+		}
+	}
+
+	if targetKind != reflect.Interface {
+		// Decimal sources are converted through their text representation
+		if dec, ok := asDecimal(reflect.Indirect(sourceVal)); ok {
+			sourceVal = reflect.ValueOf(dec.String())
+		}
+	}
+
+	if err := a.allocate(targetVal, targetKind, targetKey, sourceVal); err != nil {
+		return err
+	}
+
 	switch targetKind {
 	case reflect.Bool:
 		err = a.assignBool(targetVal, targetKey, sourceVal, sourceKey)
@@ -227,14 +933,22 @@ func (a *assigner) assign(targetVal reflect.Value, targetKey metaKey, sourceVal
 	case reflect.Ptr:
 		addMetaKey, err = a.assignPtr(targetVal, targetKey, sourceVal, sourceKey)
 	case reflect.Slice:
-		err = a.assignSlice(targetVal, targetKey, sourceVal, sourceKey)
+		if targetVal.Type() == kvSliceType && !isArraySlice(reflect.Indirect(sourceVal).Kind()) {
+			err = a.assignKVs(targetVal, targetKey, sourceVal, sourceKey)
+			break
+		}
+		if targetVal.Type() == fieldSliceType && !isArraySlice(reflect.Indirect(sourceVal).Kind()) {
+			err = a.assignFields(targetVal, targetKey, sourceVal, sourceKey)
+			break
+		}
+		err = a.assignSlice(targetVal, targetKey, sourceVal, sourceKey, a.sliceMergeKey(field))
 	case reflect.Array:
 		err = a.assignArray(targetVal, targetKey, sourceVal, sourceKey)
 	case reflect.Func:
 		err = a.assignFunc(targetVal, targetKey, sourceVal, sourceKey)
 	default:
 		// Unsupported type
-		return fmt.Errorf("%s: unsupported type: %s", targetKey.String(), targetKind)
+		return &UnsupportedTypeError{Path: targetKey.String(), Type: targetVal.Type()}
 	}
 
 	// Mark key as used if we're tracking metadata and assignment was successful
@@ -245,11 +959,32 @@ func (a *assigner) assign(targetVal reflect.Value, targetKey metaKey, sourceVal
 	return err
 }
 
+// assignNil applies the NilSource policy to the target of a nil source.
+func (a *assigner) assignNil(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value) error {
+	switch a.config.NilSource {
+	case NilSourceDefault:
+		if sourceVal.IsValid() && targetVal.Kind() == reflect.Ptr && !targetVal.IsNil() && targetVal.CanSet() {
+			targetVal.Set(reflect.Zero(targetVal.Type()))
+		}
+	case NilSourceClear:
+		if targetVal.CanSet() {
+			targetVal.Set(reflect.Zero(targetVal.Type()))
+			a.addMetaKey(targetKey)
+		}
+	case NilSourceError:
+		return fmt.Errorf("'%s' expected a value, got nil", targetKey.String())
+	}
+	return nil
+}
+
 // assignBasic decodes a basic type (bool, int, string, etc.) and sets the
 // value to "data" of that type.
 func (a *assigner) assignBasic(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) error {
-	// Handle the case where targetVal is a valid pointer to a valid element
-	if targetVal.IsValid() && targetVal.Elem().IsValid() {
+	// Handle the case where targetVal is a valid pointer to a valid element.
+	// Values held by non-empty interfaces (e.g. io.Reader) are replaced
+	// rather than merged when the source implements the interface itself,
+	// and any value is replaced with ReplaceInterfaces.
+	if targetVal.IsValid() && targetVal.Elem().IsValid() && !a.config.ReplaceInterfaces && !a.replacesInterface(targetVal, sourceVal) {
 		elem := targetVal.Elem()
 
 		// If we can't address this element, then it's not writable. Instead,
@@ -294,6 +1029,9 @@ func (a *assigner) assignBasic(targetVal reflect.Value, targetKey metaKey, sourc
 	}
 
 	// Check if we can assign the source value to the target
+	if adjusted, ok := adjustToType(sourceVal, targetVal.Type()); ok {
+		sourceVal = adjusted
+	}
 	sourceType := sourceVal.Type()
 	if !sourceType.AssignableTo(targetVal.Type()) {
 		return fmt.Errorf(
@@ -302,10 +1040,65 @@ func (a *assigner) assignBasic(targetVal reflect.Value, targetKey metaKey, sourc
 	}
 
 	// Perform the assignment
-	targetVal.Set(sourceVal)
+	targetVal.Set(a.copyBytes(sourceVal))
 	return nil
 }
 
+// copyBytes returns a copy of byte slice values when CopyBytes is enabled,
+// so the target doesn't share its backing array with the source.
+func (a *assigner) copyBytes(val reflect.Value) reflect.Value {
+	if !a.config.CopyBytes || val.Kind() != reflect.Slice || val.Type().Elem().Kind() != reflect.Uint8 || val.IsNil() {
+		return val
+	}
+
+	copied := reflect.MakeSlice(val.Type(), val.Len(), val.Len())
+	reflect.Copy(copied, val)
+	return copied
+}
+
+// replacesInterface reports whether sourceVal should replace the value held
+// by the interface target instead of being merged into it, which is the
+// case for non-empty interfaces implemented by a different source type.
+func (a *assigner) replacesInterface(targetVal reflect.Value, sourceVal reflect.Value) bool {
+	if targetVal.Kind() != reflect.Interface || targetVal.Type().NumMethod() == 0 {
+		return false
+	}
+
+	if !sourceVal.IsValid() {
+		return false
+	}
+
+	if adjusted, ok := adjustToType(sourceVal, targetVal.Type()); ok {
+		sourceVal = adjusted
+	}
+	sourceType := sourceVal.Type()
+	return sourceType != targetVal.Elem().Type() && sourceType.AssignableTo(targetVal.Type())
+}
+
+// adjustToType returns sourceVal adjusted to be assignable to targetType
+// when only its address or its dereference is: values whose methods have
+// pointer receivers are copied to a new pointer for the interfaces they
+// implement through it, and non nil pointers are dereferenced. It reports
+// false when sourceVal is assignable as is or can't be adjusted.
+func adjustToType(sourceVal reflect.Value, targetType reflect.Type) (reflect.Value, bool) {
+	sourceType := sourceVal.Type()
+	if sourceType.AssignableTo(targetType) {
+		return sourceVal, false
+	}
+
+	if targetType.Kind() == reflect.Interface && reflect.PointerTo(sourceType).Implements(targetType) {
+		ptr := reflect.New(sourceType)
+		ptr.Elem().Set(sourceVal)
+		return ptr, true
+	}
+
+	if sourceType.Kind() == reflect.Ptr && !sourceVal.IsNil() && sourceType.Elem().AssignableTo(targetType) {
+		return sourceVal.Elem(), true
+	}
+
+	return sourceVal, false
+}
+
 // assignString assigns a value to a string target, performing type conversions as needed.
 func (a *assigner) assignString(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, _ metaKey) error {
 	// Get the source value, dereferencing pointers if necessary
@@ -313,11 +1106,22 @@ func (a *assigner) assignString(targetVal reflect.Value, targetKey metaKey, sour
 	sourceKind := sourceVal.Kind()
 
 	if isString(sourceKind) {
+		if a.config.NumberStrings && isJsonNumber(targetVal.Type()) && !isNumberText(sourceVal.String()) {
+			return fmt.Errorf("'%s' expected a number, got '%s'", targetKey.String(), sourceVal.String())
+		}
+
 		// Direct string assignment
 		targetVal.SetString(sourceVal.String())
 		return nil
 	}
 
+	if a.config.NumberStrings {
+		if str, ok := formatNumber(sourceVal); ok {
+			targetVal.SetString(str)
+			return nil
+		}
+	}
+
 	if a.config.WeaklyTypedInput {
 		if isBool(sourceKind) {
 			// Convert boolean to string ("1" for true, "0" for false)
@@ -372,13 +1176,7 @@ func (a *assigner) assignString(targetVal reflect.Value, targetKey metaKey, sour
 		}
 	}
 
-	return fmt.Errorf(
-		"'%s' expected type '%s', got unconvertible type '%s', value: '%v'",
-		targetKey.String(),
-		targetVal.Type(),
-		sourceVal.Type(),
-		sourceVal.Interface(),
-	)
+	return newUnconvertibleTypeError(targetKey, targetVal.Type(), sourceVal)
 }
 
 func (a *assigner) assignInt(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, _ metaKey) error {
@@ -387,18 +1185,34 @@ func (a *assigner) assignInt(targetVal reflect.Value, targetKey metaKey, sourceV
 	sourceType := sourceVal.Type()
 
 	if isInt(sourceKind) {
-		targetVal.SetInt(sourceVal.Int())
-		return nil
+		return a.setInt(targetVal, targetKey, sourceVal.Int())
 	}
 
 	if isUint(sourceKind) {
-		targetVal.SetInt(int64(sourceVal.Uint()))
-		return nil
+		u := sourceVal.Uint()
+		if a.config.SafeNumerics && u > math.MaxInt64 {
+			return newRangeError(targetKey, u, targetVal.Type())
+		}
+		return a.setInt(targetVal, targetKey, int64(u))
 	}
 
 	if isFloat(sourceKind) {
-		targetVal.SetInt(int64(sourceVal.Float()))
-		return nil
+		f := sourceVal.Float()
+		if a.config.SafeNumerics && (f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64) {
+			return newRangeError(targetKey, f, targetVal.Type())
+		}
+		return a.setInt(targetVal, targetKey, int64(f))
+	}
+
+	if a.config.NumberStrings && isString(sourceKind) {
+		i, err := strconv.ParseInt(sourceVal.String(), 10, targetVal.Type().Bits())
+		if err != nil && a.config.IntegerExponents && isExponent(sourceVal.String()) {
+			i, err = parseExponentInt(sourceVal.String(), targetVal.Type().Bits())
+		}
+		if err != nil {
+			return fmt.Errorf("cannot parse '%s' as int: %s", targetKey.String(), err)
+		}
+		return a.setInt(targetVal, targetKey, i)
 	}
 
 	if a.config.WeaklyTypedInput {
@@ -417,7 +1231,7 @@ func (a *assigner) assignInt(targetVal reflect.Value, targetKey metaKey, sourceV
 				str = "0"
 			}
 
-			i, err := strconv.ParseInt(str, 0, targetVal.Type().Bits())
+			i, err := a.parseInt(str, targetVal.Type().Bits())
 			if err == nil {
 				targetVal.SetInt(i)
 			} else {
@@ -430,21 +1244,17 @@ func (a *assigner) assignInt(targetVal reflect.Value, targetKey metaKey, sourceV
 	if sourceType.PkgPath() == "encoding/json" && sourceType.Name() == "Number" {
 		jn := sourceVal.Interface().(json.Number)
 		i, err := jn.Int64()
+		if err != nil && a.config.IntegerExponents && isExponent(string(jn)) {
+			i, err = parseExponentInt(string(jn), 64)
+		}
 		if err != nil {
 			return fmt.Errorf(
 				"error parsing json.Number into %s: %s", targetKey.String(), err)
 		}
-		targetVal.SetInt(i)
-		return nil
+		return a.setInt(targetVal, targetKey, i)
 	}
 
-	return fmt.Errorf(
-		"'%s' expected type '%s', got unconvertible type '%s', value: '%v'",
-		targetKey.String(),
-		targetVal.Type(),
-		sourceVal.Type(),
-		sourceVal.Interface(),
-	)
+	return newUnconvertibleTypeError(targetKey, targetVal.Type(), sourceVal)
 }
 
 func (a *assigner) assignUint(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, _ metaKey) error {
@@ -454,27 +1264,39 @@ func (a *assigner) assignUint(targetVal reflect.Value, targetKey metaKey, source
 
 	if isInt(sourceKind) {
 		i := sourceVal.Int()
+		if a.config.SafeNumerics && i < 0 {
+			return newRangeError(targetKey, i, targetVal.Type())
+		}
 		if i < 0 && !a.config.WeaklyTypedInput {
-			return fmt.Errorf("cannot parse '%s', %d overflows uint",
-				targetKey.String(), i)
+			return newOverflowError(targetKey, i, targetVal.Type())
 		}
-		targetVal.SetUint(uint64(i))
-		return nil
+		return a.setUint(targetVal, targetKey, uint64(i))
 	}
 
 	if isUint(sourceKind) {
-		targetVal.SetUint(sourceVal.Uint())
-		return nil
+		return a.setUint(targetVal, targetKey, sourceVal.Uint())
 	}
 
 	if isFloat(sourceKind) {
 		f := sourceVal.Float()
+		if a.config.SafeNumerics && (f != math.Trunc(f) || f < 0 || f >= math.MaxUint64) {
+			return newRangeError(targetKey, f, targetVal.Type())
+		}
 		if f < 0 && !a.config.WeaklyTypedInput {
-			return fmt.Errorf("cannot parse '%s', %f overflows uint",
-				targetKey.String(), f)
+			return newOverflowError(targetKey, f, targetVal.Type())
 		}
-		targetVal.SetUint(uint64(f))
-		return nil
+		return a.setUint(targetVal, targetKey, uint64(f))
+	}
+
+	if a.config.NumberStrings && isString(sourceKind) {
+		u, err := strconv.ParseUint(sourceVal.String(), 10, targetVal.Type().Bits())
+		if err != nil && a.config.IntegerExponents && isExponent(sourceVal.String()) {
+			u, err = parseExponentUint(sourceVal.String(), targetVal.Type().Bits())
+		}
+		if err != nil {
+			return fmt.Errorf("cannot parse '%s' as uint: %s", targetKey.String(), err)
+		}
+		return a.setUint(targetVal, targetKey, u)
 	}
 
 	if a.config.WeaklyTypedInput {
@@ -493,7 +1315,7 @@ func (a *assigner) assignUint(targetVal reflect.Value, targetKey metaKey, source
 				str = "0"
 			}
 
-			i, err := strconv.ParseUint(str, 0, targetVal.Type().Bits())
+			i, err := a.parseUint(str, targetVal.Type().Bits())
 			if err == nil {
 				targetVal.SetUint(i)
 			} else {
@@ -509,21 +1331,17 @@ func (a *assigner) assignUint(targetVal reflect.Value, targetKey metaKey, source
 			return fmt.Errorf("expected json.Number, got different type for '%s'", targetKey.String())
 		}
 		i, err := strconv.ParseUint(string(jn), 0, 64)
+		if err != nil && a.config.IntegerExponents && isExponent(string(jn)) {
+			i, err = parseExponentUint(string(jn), 64)
+		}
 		if err != nil {
 			return fmt.Errorf(
 				"error decoding json.Number into %s: %s", targetKey.String(), err)
 		}
-		targetVal.SetUint(i)
-		return nil
+		return a.setUint(targetVal, targetKey, i)
 	}
 
-	return fmt.Errorf(
-		"'%s' expected type '%s', got unconvertible type '%s', value: '%v'",
-		targetKey.String(),
-		targetVal.Type(),
-		sourceVal.Type(),
-		sourceVal.Interface(),
-	)
+	return newUnconvertibleTypeError(targetKey, targetVal.Type(), sourceVal)
 }
 
 func (a *assigner) assignBool(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) error {
@@ -550,27 +1368,46 @@ func (a *assigner) assignBool(targetVal reflect.Value, targetKey metaKey, source
 			targetVal.SetBool(sourceVal.Float() != 0)
 			return nil
 		}
+	}
 
-		if isString(sourceKind) {
-			b, err := strconv.ParseBool(sourceVal.String())
-			if err == nil {
-				targetVal.SetBool(b)
-			} else if sourceVal.String() == "" {
-				targetVal.SetBool(false)
-			} else {
-				return fmt.Errorf("cannot parse '%s' as bool: %s", sourceKey.String(), err)
-			}
-			return nil
+	if isString(sourceKind) && (a.config.WeaklyTypedInput || a.config.BoolStrings) {
+		b, err := a.parseBool(sourceVal.String())
+		if err != nil {
+			return fmt.Errorf("cannot parse '%s' as bool: %s", sourceKey.String(), err)
 		}
+		targetVal.SetBool(b)
+		return nil
 	}
 
-	return fmt.Errorf(
-		"'%s' expected type '%s', got unconvertible type '%s', value: '%v'",
-		targetKey.String(),
-		targetVal.Type(),
-		sourceVal.Type(),
-		sourceVal.Interface(),
-	)
+	return newUnconvertibleTypeError(targetKey, targetVal.Type(), sourceVal)
+}
+
+// parseBool parses str using the configured boolean vocabulary. Surrounding
+// whitespace is ignored, words are matched case-insensitively and empty
+// strings are false.
+func (a *assigner) parseBool(str string) (bool, error) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return false, nil
+	}
+
+	if len(a.config.TrueStrings) == 0 && len(a.config.FalseStrings) == 0 {
+		return strconv.ParseBool(strings.ToLower(str))
+	}
+
+	for _, word := range a.config.TrueStrings {
+		if strings.EqualFold(word, str) {
+			return true, nil
+		}
+	}
+
+	for _, word := range a.config.FalseStrings {
+		if strings.EqualFold(word, str) {
+			return false, nil
+		}
+	}
+
+	return false, fmt.Errorf("'%s' is not a recognized boolean value", str)
 }
 
 func (a *assigner) assignFloat(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, _ metaKey) error {
@@ -579,27 +1416,35 @@ func (a *assigner) assignFloat(targetVal reflect.Value, targetKey metaKey, sourc
 	sourceType := sourceVal.Type()
 
 	if isInt(sourceKind) {
-		targetVal.SetFloat(float64(sourceVal.Int()))
+		i := sourceVal.Int()
+		f := a.toTargetFloat(targetVal, float64(i))
+		if a.config.SafeNumerics && (f >= math.MaxInt64 || int64(f) != i) {
+			return newRangeError(targetKey, i, targetVal.Type())
+		}
+		targetVal.SetFloat(f)
 		return nil
 	}
 
 	if isUint(sourceKind) {
-		targetVal.SetFloat(float64(sourceVal.Uint()))
+		u := sourceVal.Uint()
+		f := a.toTargetFloat(targetVal, float64(u))
+		if a.config.SafeNumerics && (f >= math.MaxUint64 || uint64(f) != u) {
+			return newRangeError(targetKey, u, targetVal.Type())
+		}
+		targetVal.SetFloat(f)
 		return nil
 	}
 
 	if isFloat(sourceKind) {
-		f := sourceVal.Float()
-		if err := a.checkNaNAndInf(targetKey, f); err != nil {
-			if a.config.WeaklyTypedInput {
-				targetVal.SetFloat(0)
-			} else {
-				return err
-			}
-		} else {
-			targetVal.SetFloat(f)
+		return a.setFloatValue(targetVal, targetKey, sourceVal.Float())
+	}
+
+	if a.config.NumberStrings && isString(sourceKind) {
+		f, err := strconv.ParseFloat(sourceVal.String(), targetVal.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot parse '%s' as float: %s", targetKey.String(), err)
 		}
-		return nil
+		return a.setFloatValue(targetVal, targetKey, f)
 	}
 
 	if a.config.WeaklyTypedInput {
@@ -642,13 +1487,7 @@ func (a *assigner) assignFloat(targetVal reflect.Value, targetKey metaKey, sourc
 		return a.setFloatValue(targetVal, targetKey, i)
 	}
 
-	return fmt.Errorf(
-		"'%s' expected type '%s', got unconvertible type '%s', value: '%v'",
-		targetKey.String(),
-		targetVal.Type(),
-		sourceVal.Type(),
-		sourceVal.Interface(),
-	)
+	return newUnconvertibleTypeError(targetKey, targetVal.Type(), sourceVal)
 }
 
 // setFloatValue sets the float value after checking for NaN and Inf
@@ -660,10 +1499,41 @@ func (a *assigner) setFloatValue(targetVal reflect.Value, key metaKey, f float64
 		}
 		return err
 	}
+	if a.config.SafeNumerics && a.toTargetFloat(targetVal, f) != f {
+		return newRangeError(key, f, targetVal.Type())
+	}
 	targetVal.SetFloat(f)
 	return nil
 }
 
+// toTargetFloat rounds f to the precision of the float target.
+func (a *assigner) toTargetFloat(targetVal reflect.Value, f float64) float64 {
+	if targetVal.Kind() == reflect.Float32 {
+		return float64(float32(f))
+	}
+	return f
+}
+
+// setInt sets the integer value, rejecting values that overflow the
+// target type when SafeNumerics is enabled.
+func (a *assigner) setInt(targetVal reflect.Value, key metaKey, i int64) error {
+	if a.config.SafeNumerics && targetVal.OverflowInt(i) {
+		return newRangeError(key, i, targetVal.Type())
+	}
+	targetVal.SetInt(i)
+	return nil
+}
+
+// setUint sets the unsigned integer value, rejecting values that overflow
+// the target type when SafeNumerics is enabled.
+func (a *assigner) setUint(targetVal reflect.Value, key metaKey, u uint64) error {
+	if a.config.SafeNumerics && targetVal.OverflowUint(u) {
+		return newRangeError(key, u, targetVal.Type())
+	}
+	targetVal.SetUint(u)
+	return nil
+}
+
 // checkNaNAndInf checks if a float value is NaN or Infinity and returns appropriate error if needed
 func (a *assigner) checkNaNAndInf(key metaKey, f float64) error {
 	if math.IsNaN(f) || math.IsInf(f, 0) {
@@ -672,6 +1542,67 @@ func (a *assigner) checkNaNAndInf(key metaKey, f float64) error {
 	return nil
 }
 
+// parseInt parses a weakly typed integer string according to the
+// IntegerFormats policy.
+func (a *assigner) parseInt(str string, bitSize int) (int64, error) {
+	base, err := a.integerBase(str)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.ParseInt(str, base, bitSize)
+	if err != nil && a.config.IntegerExponents && isExponent(str) {
+		return parseExponentInt(str, bitSize)
+	}
+	return i, err
+}
+
+// parseUint is like parseInt for unsigned integers, a leading plus sign
+// is accepted.
+func (a *assigner) parseUint(str string, bitSize int) (uint64, error) {
+	str = strings.TrimPrefix(str, "+")
+	base, err := a.integerBase(str)
+	if err != nil {
+		return 0, err
+	}
+	u, err := strconv.ParseUint(str, base, bitSize)
+	if err != nil && a.config.IntegerExponents && isExponent(str) {
+		return parseExponentUint(str, bitSize)
+	}
+	return u, err
+}
+
+// integerBase returns the strconv base to parse str with, or an error if
+// its format is denied by IntegerFormats.
+func (a *assigner) integerBase(str string) (int, error) {
+	formats := a.config.IntegerFormats
+	if formats == 0 || formats&IntegerAll == IntegerAll {
+		return 0, nil
+	}
+
+	body := strings.ToLower(strings.TrimLeft(str, "+-"))
+	switch {
+	case strings.HasPrefix(body, "0x"):
+		if formats&IntegerHex == 0 {
+			return 0, fmt.Errorf("hexadecimal integer '%s' is not allowed", str)
+		}
+		return 0, nil
+	case strings.HasPrefix(body, "0b"):
+		if formats&IntegerBinary == 0 {
+			return 0, fmt.Errorf("binary integer '%s' is not allowed", str)
+		}
+		return 0, nil
+	case strings.HasPrefix(body, "0o"):
+		if formats&IntegerOctal == 0 {
+			return 0, fmt.Errorf("octal integer '%s' is not allowed", str)
+		}
+		return 0, nil
+	case len(body) > 1 && body[0] == '0' && formats&IntegerOctal != 0:
+		return 0, nil
+	}
+
+	return 10, nil
+}
+
 func (a *assigner) assignMap(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) error {
 	sourceVal = reflect.Indirect(sourceVal)
 
@@ -712,7 +1643,7 @@ func (a *assigner) assignMapFromSlice(targetVal reflect.Value, targetKey metaKey
 		return nil
 	}
 
-	if targetVal.IsNil() {
+	if targetVal.IsNil() || a.config.ZeroFields {
 		targetVal.Set(reflect.MakeMap(reflect.MapOf(targetKeyType, targetElemType)))
 	}
 
@@ -743,7 +1674,8 @@ func (a *assigner) assignMapFromMap(targetVal reflect.Value, targetKey metaKey,
 	}
 
 	// Accumulate errors
-	errors := make([]string, 0)
+	errors := make([]error, 0)
+	collection := make([]*CollectionError, 0)
 
 	// If the input data is empty, then we just match what the input data is.
 	if sourceVal.Len() == 0 {
@@ -752,33 +1684,62 @@ func (a *assigner) assignMapFromMap(targetVal reflect.Value, targetKey metaKey,
 		return nil
 	}
 
-	if targetVal.IsNil() {
+	if targetVal.IsNil() || a.config.ZeroFields {
 		targetVal.Set(reflect.MakeMap(reflect.MapOf(targetValKeyType, targetValElemType)))
 	}
 
+	if a.assignMapFast(targetVal, targetKey, sourceVal) {
+		return nil
+	}
+
 	for _, srcKey := range sourceVal.MapKeys() {
-		kStr := fmt.Sprintf("%v", srcKey.Interface())
+		kStr := mapKeyString(srcKey)
 
 		targetElem := reflect.Indirect(reflect.New(targetValElemType))
 		sourceElem := sourceVal.MapIndex(srcKey)
 
-		childTargetKey := targetKey.newChild(reflect.Map, kStr)
 		childSourceKey := sourceKey.newChild(reflect.Map, kStr)
+		targetMapKey := srcKey
+		if alias, ok := a.keyAlias(sourceKey, kStr); ok {
+			kStr = alias
+			targetMapKey = reflect.ValueOf(alias)
+		}
+		childTargetKey := targetKey.newChild(reflect.Map, kStr)
 
 		if a.shouldSkipKey(childTargetKey, childSourceKey) {
 			continue
 		}
 
+		if a.skipUnsupported(sourceElem) {
+			a.addMetaUnused(childSourceKey)
+			continue
+		}
+
 		// First decode the key into the proper type
 		currentKey := reflect.Indirect(reflect.New(targetValKeyType))
-		if err := weakAssigner.assign(currentKey, "", srcKey, ""); err != nil {
+		if err := a.assignKey(currentKey, targetMapKey); err != nil {
 			errors = appendErrors(errors, err)
+			collection = appendElementError(collection, childTargetKey, -1, kStr, err)
+			if a.config.FailFast {
+				break
+			}
 			continue
 		}
 
+		// Map values aren't addressable, so existing values are copied
+		// and decoded into, then stored back into the map. Only values
+		// that can be merged are, the others are replaced.
+		if existing := targetVal.MapIndex(currentKey); existing.IsValid() && a.mergesMapValue(existing, sourceElem) {
+			targetElem.Set(existing)
+		}
+
 		// Next decode the data into the proper type
 		if err := a.assign(targetElem, childTargetKey, sourceElem, childSourceKey); err != nil {
 			errors = appendErrors(errors, err)
+			collection = appendElementError(collection, childTargetKey, -1, kStr, err)
+			if a.config.FailFast {
+				break
+			}
 			continue
 		}
 
@@ -787,48 +1748,299 @@ func (a *assigner) assignMapFromMap(targetVal reflect.Value, targetKey metaKey,
 
 	// If we had errors, return those
 	if len(errors) > 0 {
-		return &Error{errors}
+		return newError(errors, collection)
 	}
 
 	return nil
 }
 
+// fastMapsOptions are the options that don't change how the entries of
+// string keyed maps are assigned, see usesFastMaps.
+var fastMapsOptions = configFields(
+	// Options naming and selecting struct fields
+	"TagName", "TagNames", "Converter", "Initialisms",
+	"IncludeIgnoreFields", "IgnoreUntaggedFields", "UnnamedTag",
+	"FieldNames", "GroupKeys", "MatchName", "PluralizeKeys",
+	"PromoteUnexportedEmbedded", "UseGetters",
+	"Squash", "SquashCollisions",
+	"OmitZeroStructs", "EmptyStructAsNil", "DeepInterfaceMaps",
+	"ApplyDefaults", "Validate", "ErrorUnused",
+
+	// Existing entries always take the general path
+	"MergeInterfaceMaps",
+
+	// Conversions between different types, map entries are only copied
+	// to maps of the same type or of interfaces
+	"WeaklyTypedInput", "BoolStrings", "TrueStrings", "FalseStrings",
+	"SafeNumerics", "NumberStrings", "IntegerFormats", "IntegerExponents",
+
+	// Nil entries always take the general path
+	"NilSource", "NilPointers",
+
+	// Bookkeeping
+	"Metadata", "SortMetadata", "KeyStringification",
+	"FailFast", "Recover", "Cache", "MemoryLimit", "Metrics",
+)
+
+// usesFastMaps reports whether assignMapFast can be used with config. The
+// fast path copies map entries as is, so it is only used when the options
+// other than fastMapsOptions have their default value. New options disable
+// it until they are listed there.
+func usesFastMaps(config *AssignConfig) bool {
+	return hasDefaultsExcept(config, fastMapsOptions)
+}
+
+// configFields returns a mask of the AssignConfig fields named names,
+// indexed like the fields.
+func configFields(names ...string) []bool {
+	typ := reflect.TypeOf(AssignConfig{})
+	mask := make([]bool, typ.NumField())
+	for _, name := range names {
+		field, ok := typ.FieldByName(name)
+		if !ok {
+			panic(fmt.Sprintf("object: unknown AssignConfig field %s", name))
+		}
+		mask[field.Index[0]] = true
+	}
+	return mask
+}
+
+// hasDefaultsExcept reports whether the fields of config that aren't in
+// the mask have their zero value, which is the default of every option.
+func hasDefaultsExcept(config *AssignConfig, mask []bool) bool {
+	val := reflect.ValueOf(config).Elem()
+	for i, masked := range mask {
+		if !masked && !val.Field(i).IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// assignMapFast copies the entries of string keyed maps that don't need
+// element-wise conversion, avoiding the per entry reflection overhead on
+// large maps. It reports false when the general path must be used.
+func (a *assigner) assignMapFast(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value) bool {
+	if !a.fastMaps {
+		return false
+	}
+
+	switch target := targetVal.Interface().(type) {
+	case map[string]any:
+		source, ok := sourceVal.Interface().(map[string]any)
+		if !ok || len(target) > 0 {
+			return false
+		}
+
+		// Nil values are subject to the NilSource policy
+		for _, v := range source {
+			if isNilSource(reflect.ValueOf(v)) {
+				return false
+			}
+		}
+
+		for k, v := range source {
+			target[k] = v
+			if a.tracksKeys() {
+				a.addMetaKey(targetKey.newChild(reflect.Map, k))
+			}
+		}
+		return true
+
+	case map[string]string:
+		source, ok := sourceVal.Interface().(map[string]string)
+		if !ok {
+			return false
+		}
+
+		for k, v := range source {
+			target[k] = v
+			if a.tracksKeys() {
+				a.addMetaKey(targetKey.newChild(reflect.Map, k))
+			}
+		}
+		return true
+	}
+
+	// Maps of the same type with scalar values are copied as is
+	targetType := targetVal.Type()
+	if sourceVal.Type() != targetType || targetType.Key().Kind() != reflect.String || !isScalar(targetType.Elem().Kind()) {
+		return false
+	}
+
+	iter := sourceVal.MapRange()
+	for iter.Next() {
+		targetVal.SetMapIndex(iter.Key(), iter.Value())
+		if a.tracksKeys() {
+			a.addMetaKey(targetKey.newChild(reflect.Map, iter.Key().String()))
+		}
+	}
+	return true
+}
+
 func (a *assigner) assignMapFromStruct(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) error {
+	if err := a.squashCollisions(sourceVal.Type(), sourceKey); err != nil {
+		return err
+	}
+
 	targetMapType := targetVal.Type()
 	targetKeyType := targetMapType.Key()
 	targetElemType := targetMapType.Elem()
 
-	if targetVal.IsNil() {
+	if targetVal.IsNil() || a.config.ZeroFields {
 		targetVal.Set(reflect.MakeMap(reflect.MapOf(targetKeyType, targetElemType)))
+		if a.config.EmptyStructAsNil {
+			defer func() {
+				if targetVal.Len() == 0 {
+					targetVal.Set(reflect.Zero(targetMapType))
+				}
+			}()
+		}
 	}
 
-	sourceFields := a.flattenStruct(sourceVal)
+	sourceFields, err := a.mapFields(a.flattenStruct(sourceVal, false), targetMapType, sourceKey)
+	if err != nil {
+		return err
+	}
 	for _, srcField := range sourceFields {
+		if a.config.IgnoreUntaggedFields && !a.hasTag(srcField.field) {
+			continue
+		}
+
+		// Zero values are checked before fields are converted, which
+		// would hide their IsZero method
+		omitZero := srcField.OmitZero && isOmitZero(srcField.fieldVal)
+
+		if formatted, ok := a.formatTime(srcField.fieldVal, targetElemType.Kind() == reflect.String); ok {
+			srcField.fieldVal = formatted
+		}
+
+		if value, ok := a.unwrapValue(srcField.fieldVal); ok {
+			srcField.fieldVal = value
+		}
+
+		if marshaled, ok, err := a.marshalJSONSource(targetElemType.Kind(), sourceKey.newChild(reflect.Struct, srcField.displayName), srcField.fieldVal); err != nil {
+			return err
+		} else if ok {
+			if !marshaled.IsValid() {
+				marshaled = reflect.Zero(targetElemType)
+			}
+			srcField.fieldVal = marshaled
+		}
+
+		// Decimal fields are emitted as their text representation
+		if dec, ok := asDecimal(srcField.fieldVal); ok {
+			srcField.fieldVal = reflect.ValueOf(dec.String())
+		}
+
+		// Bit flag fields are emitted as the names of their bits
+		if names, ok := flagNames(srcField.fieldVal); ok {
+			srcField.fieldVal = names
+		}
+
+		// Durations tagged with a unit are emitted as numbers of that unit
+		if srcField.Unit != "" {
+			value, err := durationInUnit(srcField.fieldVal, srcField.Unit, sourceKey.newChild(reflect.Struct, srcField.displayName))
+			if err != nil {
+				return err
+			}
+			srcField.fieldVal = value
+		}
+
+		if srcField.Scale != "" {
+			value, err := scaledValue(srcField.fieldVal, srcField.Scale, sourceKey.newChild(reflect.Struct, srcField.displayName))
+			if err != nil {
+				return err
+			}
+			srcField.fieldVal = value
+		}
+
+		// Booleans and numbers tagged with the string option are emitted
+		// as strings
+		if srcField.String {
+			if formatted, ok := formatStringOption(srcField.fieldVal); ok {
+				srcField.fieldVal = formatted
+			}
+		}
+
+		groups, name := a.groupKey(targetMapType, srcField.actualName)
+		targetFieldKey := groupedKey(targetKey, groups, name)
+		sourceFieldKey := sourceKey.newChild(reflect.Struct, srcField.displayName)
+
+		if omitZero || srcField.OmitEmpty && a.isOmitEmpty(srcField.fieldVal) {
+			a.addMetaUnused(sourceFieldKey)
+			continue
+		}
+
+		if srcField.When != "" && !a.whenInStruct(sourceVal, srcField.When) {
+			a.addMetaUnused(sourceFieldKey)
+			continue
+		}
+
+		if srcField.fieldVal.Kind() == reflect.Ptr && srcField.fieldVal.IsNil() {
+			switch a.config.NilPointers {
+			case NilPointerUntyped:
+				if targetElemType.Kind() == reflect.Interface {
+					srcField.fieldVal = reflect.Zero(targetElemType)
+				}
+			case NilPointerZero:
+				srcField.fieldVal = reflect.Zero(srcField.fieldVal.Type().Elem())
+			case NilPointerOmit:
+				a.addMetaUnused(sourceFieldKey)
+				continue
+			}
+		}
+
+		if a.skipUnsupported(srcField.fieldVal) {
+			a.addMetaUnused(sourceFieldKey)
+			continue
+		}
+
 		// Next get the actual value of this field and verify it is assignable
 		// to the map value.
 		if !srcField.fieldVal.Type().AssignableTo(targetVal.Type().Elem()) {
 			return fmt.Errorf("cannot assign type '%s' to map value field of type '%s'", srcField.fieldVal.Type(), targetVal.Type().Elem())
 		}
 
-		targetFieldKey := targetKey.newChild(reflect.Map, srcField.actualName)
-		sourceFieldKey := sourceKey.newChild(reflect.Struct, srcField.displayName)
-
 		if a.shouldSkipKey(targetFieldKey, sourceFieldKey) {
 			continue
 		}
 
-		keyVal := reflect.Indirect(reflect.New(targetKeyType))
-		if err := weakAssigner.assign(keyVal, "", srcField.ActualNameVal(), ""); err != nil {
+		fieldMap := targetVal
+		nameVal := srcField.ActualNameVal()
+		if len(groups) > 0 {
+			group, err := a.groupMap(targetVal, targetKey, groups)
+			if err != nil {
+				return err
+			}
+			fieldMap, nameVal = group, reflect.ValueOf(name)
+		}
+
+		keyVal := reflect.Indirect(reflect.New(fieldMap.Type().Key()))
+		if err := a.assignKey(keyVal, nameVal); err != nil {
 			return fmt.Errorf("error converting map key '%s': %w", srcField.actualName, err)
 		}
 
+		if a.config.DeepInterfaceMaps && targetElemType.Kind() == reflect.Interface {
+			value, err := a.genericValue(srcField.fieldVal, targetFieldKey, sourceFieldKey)
+			if err != nil {
+				return err
+			}
+			if !value.IsValid() {
+				value = reflect.Zero(targetElemType)
+			}
+			fieldMap.SetMapIndex(keyVal, value)
+			a.addMetaKey(targetFieldKey)
+			continue
+		}
+
 		srcFieldKind := srcField.fieldVal.Kind()
 
 		if isStruct(srcFieldKind) { // this is an embedded struct, so handle it differently
 			sourceFieldType := srcField.fieldVal.Type()
 			// Check if struct can be directly assigned to map element
 			if sourceFieldType.AssignableTo(targetElemType) {
-				targetVal.SetMapIndex(keyVal, srcField.fieldVal)
+				fieldMap.SetMapIndex(keyVal, srcField.fieldVal)
 				a.addMetaKey(targetFieldKey)
 				continue
 			}
@@ -845,24 +2057,115 @@ func (a *assigner) assignMapFromStruct(targetVal reflect.Value, targetKey metaKe
 				return err
 			}
 
-			targetVal.SetMapIndex(keyVal, targetChildVal)
-			a.addMetaKey(targetFieldKey)
+			if a.config.EmptyStructAsNil && targetChildVal.Len() == 0 {
+				targetChildVal = reflect.Zero(targetElemType)
+			}
 
-			continue
-		}
+			fieldMap.SetMapIndex(keyVal, targetChildVal)
+			a.addMetaKey(targetFieldKey)
 
-		if srcField.omitempty && isEmptyValue(srcField.fieldVal) {
-			a.addMetaUnused(sourceFieldKey)
 			continue
 		}
 
-		targetVal.SetMapIndex(keyVal, srcField.fieldVal)
+		fieldMap.SetMapIndex(keyVal, a.copyBytes(srcField.fieldVal))
 		a.addMetaKey(targetFieldKey)
 	}
 
 	return nil
 }
 
+// genericValue converts the structs reachable from val, through pointers,
+// interfaces, slices, arrays and maps, into map[string]any values for
+// DeepInterfaceMaps. Containers holding structs become []any and
+// map[string]any, structs implementing encoding.TextMarshaler (such as
+// time.Time) and values without structs are returned as is. Nil pointers
+// and interfaces yield an invalid value.
+func (a *assigner) genericValue(val reflect.Value, targetKey metaKey, sourceKey metaKey) (reflect.Value, error) {
+	if formatted, ok := a.formatTime(val, false); ok {
+		return formatted, nil
+	}
+
+	if !holdsStructs(val.Type()) {
+		return a.copyBytes(val), nil
+	}
+
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return reflect.Value{}, nil
+		}
+		return a.genericValue(val.Elem(), targetKey, sourceKey)
+
+	case reflect.Struct:
+		if dec, ok := asDecimal(val); ok {
+			return reflect.ValueOf(dec.String()), nil
+		}
+		child := reflect.MakeMap(anyMapType)
+		if err := a.assignMapFromStruct(child, targetKey, val, sourceKey); err != nil {
+			return reflect.Value{}, err
+		}
+		return child, nil
+
+	case reflect.Slice, reflect.Array:
+		if val.Kind() == reflect.Slice && val.IsNil() {
+			return reflect.Zero(anySliceType), nil
+		}
+		result := make([]any, val.Len())
+		for i := range result {
+			k := strconv.Itoa(i)
+			elem, err := a.genericValue(val.Index(i), targetKey.newChild(reflect.Slice, k), sourceKey.newChild(reflect.Slice, k))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if elem.IsValid() {
+				result[i] = elem.Interface()
+			}
+		}
+		return reflect.ValueOf(result), nil
+
+	case reflect.Map:
+		if val.IsNil() {
+			return reflect.Zero(anyMapType), nil
+		}
+		result := make(map[string]any, val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			k := mapKeyString(iter.Key())
+			elem, err := a.genericValue(iter.Value(), targetKey.newChild(reflect.Map, k), sourceKey.newChild(reflect.Map, k))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if elem.IsValid() {
+				result[k] = elem.Interface()
+			} else {
+				result[k] = nil
+			}
+		}
+		return reflect.ValueOf(result), nil
+	}
+
+	return val, nil
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	anySliceType      = reflect.TypeOf([]any(nil))
+)
+
+// holdsStructs reports whether values of typ may contain structs that
+// genericValue converts.
+func holdsStructs(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Struct:
+		return !typ.Implements(textMarshalerType) && !reflect.PointerTo(typ).Implements(textMarshalerType)
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return holdsStructs(typ.Elem())
+	}
+	return false
+}
+
 func (a *assigner) assignPtr(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) (bool, error) {
 	// If the input data is nil, then we want to just set the output
 	// pointer to be nil as well.
@@ -912,19 +2215,17 @@ func (a *assigner) assignFunc(targetVal reflect.Value, targetKey metaKey, source
 	// into that. Then set the value of the pointer to this type.
 	sourceVal = reflect.Indirect(sourceVal)
 	if targetVal.Type() != sourceVal.Type() {
-		return fmt.Errorf(
-			"'%s' expected type '%s', got unconvertible type '%s', value: '%v'",
-			targetKey.String(),
-			targetVal.Type(),
-			sourceVal.Type(),
-			sourceVal.Interface(),
-		)
+		return newUnconvertibleTypeError(targetKey, targetVal.Type(), sourceVal)
 	}
 	targetVal.Set(sourceVal)
 	return nil
 }
 
-func (a *assigner) assignSlice(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) error {
+// assignSlice assigns sourceVal to a slice target element by element.
+// Existing elements are decoded into, so interface elements holding
+// concrete values are merged into those values rather than replaced, and
+// the slice is then truncated or grown to the length of the source.
+func (a *assigner) assignSlice(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey, mergeKey string) error {
 	sourceVal = reflect.Indirect(sourceVal)
 	sourceKind := sourceVal.Kind()
 
@@ -955,17 +2256,17 @@ func (a *assigner) assignSlice(targetVal reflect.Value, targetKey metaKey, sourc
 				return nil
 			}
 			// Create slice of maps of other sizes
-			return a.assignSlice(targetVal, targetKey, a.wrapSlice(sourceVal), sourceKey)
+			return a.assignSlice(targetVal, targetKey, a.wrapSlice(sourceVal), sourceKey, mergeKey)
 
 		case sourceKind == reflect.String && targetValElemType.Kind() == reflect.Uint8:
 			// Convert sourceVal from type string to type []byte
-			return a.assignSlice(targetVal, targetKey, reflect.ValueOf([]byte(sourceVal.String())), sourceKey)
+			return a.assignSlice(targetVal, targetKey, reflect.ValueOf([]byte(sourceVal.String())), sourceKey, mergeKey)
 
 		// All other types we try to convert to the slice type
 		// and "lift" it into it. i.e. a string becomes a string slice.
 		default:
 			// Just re-try this function with data as a slice.
-			return a.assignSlice(targetVal, targetKey, a.wrapSlice(sourceVal), sourceKey)
+			return a.assignSlice(targetVal, targetKey, a.wrapSlice(sourceVal), sourceKey, mergeKey)
 		}
 	}
 
@@ -974,32 +2275,39 @@ func (a *assigner) assignSlice(targetVal reflect.Value, targetKey metaKey, sourc
 		return nil
 	}
 
+	if mergeKey != "" && targetVal.Len() > 0 && isStruct(indirectType(targetValElemType).Kind()) && !a.config.ZeroFields {
+		return a.mergeSliceByKey(targetVal, targetKey, sourceVal, sourceKey, mergeKey)
+	}
+
 	// Make a new slice to hold our result, same size as the original data.
 	targetValSlice := targetVal
-	if targetValSlice.IsNil() {
+	offset := 0
+	if targetValSlice.IsNil() || a.config.ZeroFields {
 		// Make a new slice to hold our result, same size as the original data.
 		targetValSlice = reflect.MakeSlice(sliceType, sourceVal.Len(), sourceVal.Len())
-	} else if targetValSlice.Len() > sourceVal.Len() {
+	} else if a.config.SliceMerge == SliceMergeAppend {
+		offset = targetValSlice.Len()
+	} else if targetValSlice.Len() > sourceVal.Len() && a.config.SliceMerge == SliceMergeTruncate {
 		targetValSlice = targetValSlice.Slice(0, sourceVal.Len())
 	}
 
 	// Accumulate any errors
-	errors := make([]string, 0)
+	errors := make([]error, 0)
+	collection := make([]*CollectionError, 0)
 
 	for i := 0; i < sourceVal.Len(); i++ {
 		sourceElem := sourceVal.Index(i)
 
 		// Ensure target slice has enough capacity
-		for targetValSlice.Len() <= i {
+		j := offset + i
+		for targetValSlice.Len() <= j {
 			targetValSlice = reflect.Append(targetValSlice, reflect.Zero(targetValElemType))
 		}
 
-		targetField := targetValSlice.Index(i)
-
-		k := strconv.Itoa(i)
+		targetField := targetValSlice.Index(j)
 
-		targetFieldKey := targetKey.newChild(reflect.Slice, k)
-		sourceFieldKey := sourceKey.newChild(reflect.Slice, k)
+		targetFieldKey := targetKey.newChild(reflect.Slice, strconv.Itoa(j))
+		sourceFieldKey := sourceKey.newChild(reflect.Slice, strconv.Itoa(i))
 
 		if a.shouldSkipKey(targetFieldKey, sourceFieldKey) {
 			continue
@@ -1007,15 +2315,19 @@ func (a *assigner) assignSlice(targetVal reflect.Value, targetKey metaKey, sourc
 
 		if err := a.assign(targetField, targetFieldKey, sourceElem, sourceFieldKey); err != nil {
 			errors = appendErrors(errors, err)
+			collection = appendElementError(collection, targetFieldKey, j, "", err)
+			if a.config.FailFast {
+				break
+			}
 		}
 	}
 
 	// Finally, set the value to the slice we built up
-	targetVal.Set(targetValSlice)
+	targetVal.Set(a.normalizeSlice(targetValSlice))
 
 	// If there were errors, we return those
 	if len(errors) > 0 {
-		return &Error{errors}
+		return newError(errors, collection)
 	}
 
 	return nil
@@ -1036,49 +2348,57 @@ func (a *assigner) assignArray(targetVal reflect.Value, targetKey metaKey, sourc
 	targetValElemType := targetValType.Elem()
 	arrayType := reflect.ArrayOf(targetValType.Len(), targetValElemType)
 
-	valArray := targetVal
-
-	if valArray.Interface() == reflect.Zero(valArray.Type()).Interface() {
-		// Check input type
-		if sourceKind != reflect.Array && sourceKind != reflect.Slice {
-			if a.config.WeaklyTypedInput {
-				switch {
-				// Empty maps turn into empty arrays
-				case sourceKind == reflect.Map:
-					if sourceVal.Len() == 0 {
-						targetVal.Set(reflect.Zero(arrayType))
-						a.addMetaKey(targetKey)
-						return nil
-					}
-
-				// All other types we try to convert to the array type
-				// and "lift" it into it. i.e. a string becomes a string array.
-				default:
-					newSlice := reflect.MakeSlice(reflect.SliceOf(sourceVal.Type()), 1, 1)
-					newSlice.Index(0).Set(sourceVal)
-					// Just re-try this function with source as a slice.
-					return a.assignArray(targetVal, targetKey, newSlice, sourceKey)
+	// Check input type
+	if sourceKind != reflect.Array && sourceKind != reflect.Slice {
+		if a.config.WeaklyTypedInput {
+			switch {
+			// Empty maps turn into empty arrays
+			case sourceKind == reflect.Map:
+				if sourceVal.Len() == 0 {
+					targetVal.Set(reflect.Zero(arrayType))
+					a.addMetaKey(targetKey)
+					return nil
 				}
+
+			// All other types we try to convert to the array type
+			// and "lift" it into it. i.e. a string becomes a string array.
+			default:
+				newSlice := reflect.MakeSlice(reflect.SliceOf(sourceVal.Type()), 1, 1)
+				newSlice.Index(0).Set(sourceVal)
+				// Just re-try this function with source as a slice.
+				return a.assignArray(targetVal, targetKey, newSlice, sourceKey)
 			}
+		}
 
-			return fmt.Errorf(
-				"'%s': source data must be an array or slice, got %s", targetKey.String(), sourceKind)
+		return fmt.Errorf(
+			"'%s': source data must be an array or slice, got %s", targetKey.String(), sourceKind)
 
-		}
-		if sourceVal.Len() > arrayType.Len() {
+	}
+	length := sourceVal.Len()
+	if length > arrayType.Len() {
+		if !a.config.TruncateArrays {
 			return fmt.Errorf(
 				"'%s': expected source data to have length less or equal to %d, got %d", targetKey.String(), arrayType.Len(), sourceVal.Len())
-
 		}
 
-		// Make a new array to hold our result, same size as the original data.
-		valArray = reflect.New(arrayType).Elem()
+		a.addMetaWarning(fmt.Sprintf(
+			"'%s': %d source element(s) truncated to fit length %d", targetKey.String(), sourceVal.Len()-arrayType.Len(), arrayType.Len()))
+		length = arrayType.Len()
+	}
+
+	// Source elements are merged into the elements of the array, wherever
+	// it is held: map values and interfaces are copied, merged into and
+	// stored back by the caller.
+	valArray := targetVal
+	if a.config.ZeroFields {
+		valArray.Set(reflect.Zero(arrayType))
 	}
 
 	// Accumulate any errors
-	errors := make([]string, 0)
+	errors := make([]error, 0)
+	collection := make([]*CollectionError, 0)
 
-	for i := 0; i < sourceVal.Len(); i++ {
+	for i := 0; i < length; i++ {
 		sourceElem := sourceVal.Index(i)
 		targetField := valArray.Index(i)
 
@@ -1092,23 +2412,26 @@ func (a *assigner) assignArray(targetVal reflect.Value, targetKey metaKey, sourc
 		}
 		if err := a.assign(targetField, targetFieldKey, sourceElem, sourceFieldKey); err != nil {
 			errors = appendErrors(errors, err)
+			collection = appendElementError(collection, targetFieldKey, i, "", err)
+			if a.config.FailFast {
+				break
+			}
 		}
 	}
 
-	// Initialize remaining elements to zero values if source is shorter than target array
-	if sourceVal.Len() < arrayType.Len() {
+	// Initialize remaining elements to zero values if source is shorter than
+	// target array, like slices are truncated. Arrays can't grow, so
+	// SliceMergeAppend keeps them like SliceMergeIndex.
+	if length < arrayType.Len() && a.config.SliceMerge == SliceMergeTruncate {
 		zeroVal := reflect.Zero(targetValElemType)
-		for i := sourceVal.Len(); i < arrayType.Len(); i++ {
+		for i := length; i < arrayType.Len(); i++ {
 			valArray.Index(i).Set(zeroVal)
 		}
 	}
 
-	// Finally, set the value to the array we built up
-	targetVal.Set(valArray)
-
 	// If there were errors, we return those
 	if len(errors) > 0 {
-		return &Error{errors}
+		return newError(errors, collection)
 	}
 
 	return nil
@@ -1123,19 +2446,37 @@ func (a *assigner) assignStruct(targetVal reflect.Value, targetKey metaKey, sour
 	case reflect.Map:
 		return a.assignStructFromMap(targetVal, targetKey, sourceVal, sourceKey)
 	case reflect.Struct:
+		// Opaque structs such as time.Time are copied as a whole
+		if sourceVal.Type() == targetVal.Type() && !hasExportedFields(targetVal.Type()) {
+			targetVal.Set(sourceVal)
+			return nil
+		}
 		return a.assignStructFromStruct(targetVal, targetKey, sourceVal, sourceKey)
 	}
-	return fmt.Errorf("'%s' expected a map, got '%s'", targetKey.String(), sourceKind)
+	return fmt.Errorf("'%s' expected a map or struct, got '%s'", targetKey.String(), sourceKind)
+}
+
+// hasExportedFields reports whether the struct type typ has exported or
+// embedded fields.
+func hasExportedFields(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.IsExported() || field.Anonymous {
+			return true
+		}
+	}
+	return false
 }
 
 type fieldInfo struct {
 	field          reflect.StructField
 	fieldVal       reflect.Value
+	parent         reflect.Type
 	displayName    string
 	displayNameVal reflect.Value
 	actualName     string
 	actualNameVal  reflect.Value
-	omitempty      bool
+	TagOptions
 }
 
 func (info *fieldInfo) DisplayNameVal() reflect.Value {
@@ -1152,44 +2493,53 @@ func (info *fieldInfo) ActualNameVal() reflect.Value {
 	return info.actualNameVal
 }
 
-func (a *assigner) flattenStruct(val reflect.Value) map[string]fieldInfo {
+// flattenStruct collects the fields of val, squashing embedded structs.
+// Nil embedded pointers of targets are allocated when allocate is true,
+// sources are never modified.
+func (a *assigner) flattenStruct(val reflect.Value, allocate bool) map[string]fieldInfo {
 
 	// This slice will keep track of all the structs we'll be decoding.
 	// There can be more than one struct if there are embedded structs
-	// that are squashed.
-	structs := make([]reflect.Value, 1, 5)
-	structs[0] = val
+	// that are squashed. Each struct records the struct embedding it, so
+	// embedded pointers leading back to an ancestor aren't walked again.
+	structs := make([]embeddedStruct, 1, 5)
+	structs[0] = embeddedStruct{val: val, parent: -1}
 
 	// Estimate capacity to improve performance
 	fields := make(map[string]fieldInfo, val.NumField())
 
-	for len(structs) > 0 {
-		structVal := structs[0]
-		structs = structs[1:]
+	for current := 0; current < len(structs); current++ {
+		structVal := structs[current].val
 
 		structType := structVal.Type()
+		tags := a.structTags(structType)
 		for i := 0; i < structType.NumField(); i++ {
 			field := structType.Field(i)
 			fieldVal := structVal.Field(i)
 
-			if !field.IsExported() {
+			if !field.IsExported() && !(a.config.PromoteUnexportedEmbedded && isEmbeddedStruct(field)) {
 				continue
 			}
 
-			actualName, omitempty, skip := a.parseTag(field)
-			if skip {
+			actualName, opts := tags[i].name, tags[i].opts
+			if opts.Skip {
 				continue
 			}
 
-			// Only check IsZero if omitempty is true to avoid unnecessary expensive operations
-			if omitempty && isZeroValue(fieldVal) {
+			// Nil embedded pointers tagged with omitempty or omitzero are
+			// left out entirely
+			if (opts.OmitEmpty || opts.OmitZero) && field.Anonymous && fieldVal.Kind() == reflect.Ptr && fieldVal.IsNil() {
 				continue
 			}
 
-			if field.Anonymous { // Field is an embedded type
-				if field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct { // Field is an embedded pointer to struct
-
-					if fieldVal.IsNil() && fieldVal.CanSet() {
+			if (field.Anonymous || opts.Squash) && a.squashable(field, opts) { // Field is an embedded or squashed struct
+				if field.Type.Kind() == reflect.Ptr { // Field is an embedded pointer to struct
+					// The fields of an ancestor are shadowed by the fields
+					// already found, and walking it again never ends.
+					if embedsType(structs, current, field.Type.Elem()) {
+						continue
+					}
+					if allocate && fieldVal.IsNil() && fieldVal.CanSet() {
 						fieldVal.Set(reflect.New(field.Type.Elem())) // Initialize fieldVal
 						fieldVal = fieldVal.Elem()
 					} else {
@@ -1201,12 +2551,14 @@ func (a *assigner) flattenStruct(val reflect.Value) map[string]fieldInfo {
 					}
 				}
 
-				if fieldVal.Kind() == reflect.Struct {
-					structs = append(structs, fieldVal)
-					continue
-				}
+				structs = append(structs, embeddedStruct{val: fieldVal, parent: current})
+				continue
 			}
 
+			// Other embedded types, such as slice and array aliases, opaque
+			// structs like time.Time and structs that aren't squashed, are
+			// regular fields named by their tag or type name.
+
 			// Check if field already exists to avoid overwriting
 			if _, exist := fields[field.Name]; exist {
 				// Already exists, ignore embed struct's field name
@@ -1216,9 +2568,10 @@ func (a *assigner) flattenStruct(val reflect.Value) map[string]fieldInfo {
 			fields[field.Name] = fieldInfo{
 				field:       field,
 				fieldVal:    fieldVal,
+				parent:      val.Type(),
 				displayName: field.Name,
 				actualName:  actualName,
-				omitempty:   omitempty,
+				TagOptions:  opts,
 			}
 		}
 	}
@@ -1226,6 +2579,22 @@ func (a *assigner) flattenStruct(val reflect.Value) map[string]fieldInfo {
 	return fields
 }
 
+type embeddedStruct struct {
+	val    reflect.Value
+	parent int
+}
+
+// embedsType reports whether the struct at index current of structs or one
+// of the structs embedding it has type typ.
+func embedsType(structs []embeddedStruct, current int, typ reflect.Type) bool {
+	for i := current; i >= 0; i = structs[i].parent {
+		if structs[i].val.Type() == typ {
+			return true
+		}
+	}
+	return false
+}
+
 // isZeroValue is a more efficient version of reflect.Value.IsZero
 // It avoids the expensive IsZero call for common types
 func isZeroValue(v reflect.Value) bool {
@@ -1277,46 +2646,132 @@ func (a *assigner) assignStructFromMap(targetVal reflect.Value, targetKey metaKe
 
 	unusedMapKeys := make(map[string]struct{})
 	for _, k := range sourceVal.MapKeys() {
-		unusedMapKeys[k.String()] = struct{}{}
+		unusedMapKeys[mapKeyString(k)] = struct{}{}
+	}
+
+	if err := a.squashCollisions(targetVal.Type(), targetKey); err != nil {
+		return err
 	}
 
-	targetFields := a.flattenStruct(targetVal)
+	targetFields := a.flattenStruct(targetVal, true)
 
 	// Pre-create mapKey value for performance optimization
 	mapKey := reflect.New(sourceTypeKey).Elem()
 
-	errors := make([]string, 0)
-	for _, targetField := range targetFields {
+	var sortedKeys []reflect.Value
+	var aliasedKeys, normalizedKeys map[string]reflect.Value
+	if len(a.config.KeyAliases) > 0 {
+		aliasedKeys = a.aliasedKeys(sourceVal, sourceKey)
+	}
+	if a.config.SourceKeyNormalizer != nil {
+		normalizedKeys = a.normalizedKeys(sourceVal)
+	}
+
+	errors := make([]error, 0)
+	collection := make([]*CollectionError, 0)
+	var failed map[string]bool
+	for name, targetField := range targetFields {
+		a.renameField(&targetField, targetKey)
 
-		if err := weakAssigner.assign(mapKey, "", targetField.ActualNameVal(), ""); err != nil {
+		if err := a.assignKey(mapKey, targetField.ActualNameVal()); err != nil {
 			errors = appendErrors(errors, err)
+			collection = appendCollectionErrors(collection, err)
+			failed = markFailed(failed, name)
+			if a.config.FailFast {
+				break
+			}
 			continue
 		}
 
 		targetFieldKey := targetKey.newChild(reflect.Struct, targetField.displayName)
 
+		if targetField.When != "" && !a.whenInMap(sourceVal, targetField.When) {
+			a.addMetaUnset(targetFieldKey, UnsetSkipped)
+			continue
+		}
+
 		value := sourceVal.MapIndex(mapKey)
+		sourceName := targetField.actualName
+		if k, ok := aliasedKeys[targetField.actualName]; ok && !value.IsValid() {
+			value = sourceVal.MapIndex(k)
+			sourceName = mapKeyString(k)
+		}
+		if !value.IsValid() && targetField.Alt != "" {
+			for _, alt := range strings.Split(targetField.Alt, "|") {
+				if err := a.assignKey(mapKey, reflect.ValueOf(alt)); err != nil {
+					continue
+				}
+				if value = sourceVal.MapIndex(mapKey); value.IsValid() {
+					sourceName = alt
+					a.addMetaAltKey(targetFieldKey, alt)
+					break
+				}
+			}
+		}
+		if !value.IsValid() && normalizedKeys != nil {
+			if k, ok := normalizedKeys[a.config.SourceKeyNormalizer(sourceName)]; ok {
+				value = sourceVal.MapIndex(k)
+				sourceName = mapKeyString(k)
+			}
+		}
+		if !value.IsValid() && a.config.PluralizeKeys {
+			if v, k, ok := a.pluralValue(sourceVal, targetField.actualName, targetField.fieldVal.Type()); ok {
+				value = v
+				sourceName = k
+			}
+		}
+		if !value.IsValid() && a.config.MatchName != nil {
+			if sortedKeys == nil {
+				sortedKeys = sortedMapKeys(sourceVal)
+			}
+			for _, k := range sortedKeys {
+				if name := mapKeyString(k); a.config.MatchName(name, targetField.actualName) {
+					value = sourceVal.MapIndex(k)
+					sourceName = name
+					break
+				}
+			}
+		}
 		if !value.IsValid() {
-			a.addMetaUnset(targetFieldKey)
+			a.addMetaUnset(targetFieldKey, UnsetMissing)
+			if a.config.ApplyDefaults {
+				if err := a.assignDefault(targetField, targetFieldKey); err != nil {
+					errors = appendErrors(errors, err)
+					failed = markFailed(failed, name)
+					if a.config.FailFast {
+						break
+					}
+				}
+			}
 			continue
 		}
 
-		sourceFieldKey := sourceKey.newChild(reflect.Map, targetField.actualName)
+		sourceFieldKey := sourceKey.newChild(reflect.Map, sourceName)
 
 		if a.shouldSkipKey(targetFieldKey, sourceFieldKey) {
+			a.addMetaUnset(targetFieldKey, UnsetSkipped)
 			continue
 		}
 
 		if !targetField.fieldVal.CanSet() {
-			a.addMetaUnset(targetFieldKey)
+			a.addMetaUnset(targetFieldKey, UnsetUnsettable)
 			continue
 		}
 
 		// Remove processed key
-		delete(unusedMapKeys, targetField.actualName)
+		delete(unusedMapKeys, sourceName)
 
-		if err := a.assign(targetField.fieldVal, targetFieldKey, value, sourceFieldKey); err != nil {
+		if targetField.Zero {
+			targetField.fieldVal.Set(reflect.Zero(targetField.fieldVal.Type()))
+		}
+
+		if err := a.assignField(targetField, targetFieldKey, value, sourceFieldKey); err != nil {
 			errors = appendErrors(errors, err)
+			collection = appendCollectionErrors(collection, err)
+			failed = markFailed(failed, name)
+			if a.config.FailFast {
+				break
+			}
 		}
 	}
 
@@ -1324,30 +2779,80 @@ func (a *assigner) assignStructFromMap(targetVal reflect.Value, targetKey metaKe
 		a.addMetaUnused(sourceKey.newChild(reflect.Map, k))
 	}
 
+	if a.config.Validate && !(a.config.FailFast && len(errors) > 0) {
+		errors = a.validateFields(targetFields, targetKey, failed, errors)
+	}
+
+	if a.config.ErrorUnused && len(unusedMapKeys) > 0 && !(a.config.FailFast && len(errors) > 0) {
+		keys := make([]string, 0, len(unusedMapKeys))
+		for k := range unusedMapKeys {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		errors = append(errors, &UnusedKeyError{
+			Path:       targetKey.String(),
+			Keys:       keys,
+			SourceType: sourceType,
+			TargetType: targetVal.Type(),
+		})
+	}
+
 	if len(errors) > 0 {
-		return &Error{errors}
+		return newError(errors, collection)
 	}
 
 	return nil
 }
 
 func (a *assigner) assignStructFromStruct(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) error {
-	targetFields := a.flattenStruct(targetVal)
-	sourceFields := a.flattenStruct(sourceVal)
+	if err := a.squashCollisions(targetVal.Type(), targetKey); err != nil {
+		return err
+	}
+	if err := a.squashCollisions(sourceVal.Type(), sourceKey); err != nil {
+		return err
+	}
 
-	errors := make([]string, 0)
+	targetFields := a.flattenStruct(targetVal, true)
+	sourceFields := a.flattenStruct(sourceVal, false)
+
+	errors := make([]error, 0)
+	collection := make([]*CollectionError, 0)
+	var failed map[string]bool
 	for tfieldName, targetField := range targetFields {
 		targetFieldKey := targetKey.newChild(reflect.Struct, targetField.displayName)
 
+		if targetField.When != "" && !a.whenInStruct(sourceVal, targetField.When) {
+			a.addMetaUnset(targetFieldKey, UnsetSkipped)
+			continue
+		}
+
 		sourceField, exist := sourceFields[tfieldName]
+		if !exist && a.config.UseGetters {
+			sourceField, exist = getterField(sourceVal, tfieldName)
+		}
 		if !exist {
-			a.addMetaUnset(targetFieldKey)
+			a.addMetaUnset(targetFieldKey, UnsetMissing)
+			if a.config.ApplyDefaults {
+				if err := a.assignDefault(targetField, targetFieldKey); err != nil {
+					errors = appendErrors(errors, err)
+					failed = markFailed(failed, tfieldName)
+					if a.config.FailFast {
+						break
+					}
+				}
+			}
+			continue
+		}
+
+		if sourceField.OmitEmpty && a.isOmitEmpty(sourceField.fieldVal) || sourceField.OmitZero && isOmitZero(sourceField.fieldVal) {
+			a.addMetaUnset(targetFieldKey, UnsetSkipped)
 			continue
 		}
 
 		sourceFieldKey := sourceKey.newChild(reflect.Struct, sourceField.displayName)
 
 		if a.shouldSkipKey(targetFieldKey, sourceFieldKey) {
+			a.addMetaUnset(targetFieldKey, UnsetSkipped)
 			continue
 		}
 
@@ -1357,15 +2862,24 @@ func (a *assigner) assignStructFromStruct(targetVal reflect.Value, targetKey met
 		}
 
 		if !targetField.fieldVal.CanSet() {
-			a.addMetaUnset(targetFieldKey)
+			a.addMetaUnset(targetFieldKey, UnsetUnsettable)
 			continue
 		}
 
 		// Remove processed key
 		delete(sourceFields, tfieldName)
 
-		if err := a.assign(targetField.fieldVal, targetFieldKey, sourceField.fieldVal, sourceFieldKey); err != nil {
+		if targetField.Zero {
+			targetField.fieldVal.Set(reflect.Zero(targetField.fieldVal.Type()))
+		}
+
+		if err := a.assignField(targetField, targetFieldKey, sourceField.fieldVal, sourceFieldKey); err != nil {
 			errors = appendErrors(errors, err)
+			collection = appendCollectionErrors(collection, err)
+			failed = markFailed(failed, tfieldName)
+			if a.config.FailFast {
+				break
+			}
 		}
 	}
 
@@ -1373,13 +2887,47 @@ func (a *assigner) assignStructFromStruct(targetVal reflect.Value, targetKey met
 		a.addMetaUnused(sourceKey.newChild(reflect.Struct, displayName))
 	}
 
+	if a.config.Validate && !(a.config.FailFast && len(errors) > 0) {
+		errors = a.validateFields(targetFields, targetKey, failed, errors)
+	}
+
 	if len(errors) > 0 {
-		return &Error{errors}
+		return newError(errors, collection)
 	}
 
 	return nil
 }
 
+// getterField returns the result of the GetName method of the source
+// struct as a field, for sources such as protobuf messages that expose
+// values through getters. Pointer receiver methods are supported.
+func getterField(sourceVal reflect.Value, name string) (fieldInfo, bool) {
+	sourcePtr := sourceVal
+	if sourceVal.CanAddr() {
+		sourcePtr = sourceVal.Addr()
+	} else {
+		sourcePtr = reflect.New(sourceVal.Type())
+		sourcePtr.Elem().Set(sourceVal)
+	}
+
+	getterName := "Get" + name
+	method := sourcePtr.MethodByName(getterName)
+	if !method.IsValid() {
+		return fieldInfo{}, false
+	}
+
+	methodType := method.Type()
+	if methodType.NumIn() != 0 || methodType.NumOut() != 1 {
+		return fieldInfo{}, false
+	}
+
+	return fieldInfo{
+		fieldVal:    method.Call(nil)[0],
+		displayName: getterName,
+		actualName:  getterName,
+	}, true
+}
+
 func (a *assigner) shouldSkipKey(targetKey, sourceKey metaKey) bool {
 	// Skip empty keys as they should never be skipped
 	if targetKey == "" || sourceKey == "" {
@@ -1399,14 +2947,24 @@ func (a *assigner) shouldSkipKey(targetKey, sourceKey metaKey) bool {
 	return false
 }
 
+// tracksKeys reports whether the assigned keys are recorded in Metadata or
+// counted for Metrics.
+func (a *assigner) tracksKeys() bool {
+	return a.config.Metadata != nil || a.keys != nil
+}
+
 func (a *assigner) addMetaKey(targetKey metaKey) {
-	// Return early if metadata is not configured
-	if a.config.Metadata == nil {
+	// Skip empty keys
+	if targetKey.IsEmpty() {
 		return
 	}
 
-	// Skip empty keys
-	if targetKey.IsEmpty() {
+	if a.keys != nil {
+		*a.keys++
+	}
+
+	// Return early if metadata is not configured
+	if a.config.Metadata == nil {
 		return
 	}
 
@@ -1426,7 +2984,7 @@ func (a *assigner) addMetaUnused(sourceKey metaKey) {
 	a.config.Metadata.Unused = append(a.config.Metadata.Unused, string(sourceKey))
 }
 
-func (a *assigner) addMetaUnset(targetKey metaKey) {
+func (a *assigner) addMetaUnset(targetKey metaKey, reason UnsetReason) {
 	if a.config.Metadata == nil {
 		return
 	}
@@ -1436,6 +2994,35 @@ func (a *assigner) addMetaUnset(targetKey metaKey) {
 	}
 
 	a.config.Metadata.Unset = append(a.config.Metadata.Unset, string(targetKey))
+	if a.config.Metadata.UnsetReasons != nil {
+		a.config.Metadata.UnsetReasons[string(targetKey)] = reason
+	}
+}
+
+func (a *assigner) addMetaAltKey(targetKey metaKey, alt string) {
+	if a.config.Metadata == nil || a.config.Metadata.AltKeys == nil {
+		return
+	}
+
+	a.config.Metadata.AltKeys[string(targetKey)] = alt
+}
+
+func (a *assigner) addMetaWarning(warning string) {
+	if a.config.Metadata == nil {
+		return
+	}
+
+	a.config.Metadata.Warnings = append(a.config.Metadata.Warnings, warning)
+}
+
+// isOmitEmpty reports whether a field tagged with omitempty should be
+// omitted. Like encoding/json, structs are never considered empty unless
+// OmitZeroStructs is enabled.
+func (a *assigner) isOmitEmpty(v reflect.Value) bool {
+	if isEmptyValue(v) {
+		return true
+	}
+	return a.config.OmitZeroStructs && isStruct(v.Kind()) && isZeroValue(v)
 }
 
 func isEmptyValue(v reflect.Value) bool {
@@ -1457,28 +3044,153 @@ func isEmptyValue(v reflect.Value) bool {
 	return false
 }
 
-func (a *assigner) parseTag(field reflect.StructField) (actualName string, omitempty, skip bool) {
-	tagValue := field.Tag.Get(a.config.TagName)
+// TagOptions holds the options that follow the name in a struct field tag.
+type TagOptions struct {
+	// Skip is true for fields tagged "-", which are ignored unless
+	// IncludeIgnoreFields is set.
+	Skip bool
+
+	// OmitEmpty omits the field from the source when it is empty.
+	OmitEmpty bool
+
+	// OmitZero omits the field from the source when it is zero, as
+	// reported by its IsZero method when it has one, e.g. for time.Time.
+	OmitZero bool
+
+	// Zero clears the target field before decoding into it.
+	Zero bool
+
+	// Squash promotes the fields of a named struct field into its parent,
+	// like an embedded struct. "inline" is accepted as a YAML style alias.
+	Squash bool
+
+	// NoSquash keeps an embedded struct as a nested key instead of
+	// squashing it.
+	NoSquash bool
+
+	// Unit is the time unit of the "unit=" option, e.g. "ms". Numbers
+	// assigned to time.Duration fields are counted in this unit, and
+	// durations are converted to numbers of this unit.
+	Unit string
+
+	// Scale is the factor of the "scale=" option, e.g. "1048576". Numbers
+	// are multiplied by it when assigned to the field, and divided by it
+	// when a struct is assigned to a map.
+	Scale string
+
+	// MergeKey is the key of the "mergekey=" option, e.g. "ID". Source
+	// slices are merged into the slice of structs of the field by
+	// matching elements on this key, like SliceMergeKey does for every
+	// slice.
+	MergeKey string
+
+	// When is the key of the "when=" option. The field is only assigned
+	// when the source value of that key, a sibling of the field, is true.
+	When string
+
+	// String is set by the "string" option of encoding/json: boolean and
+	// number fields are parsed from strings, even without
+	// WeaklyTypedInput, and emitted as strings when a struct is assigned
+	// to a map.
+	String bool
+
+	// Alt holds the keys of the "alt=" option, separated by "|", e.g.
+	// "old_name|legacy_name". Source maps without the key of the field
+	// populate it from the first of these keys found, which is recorded
+	// in Metadata.AltKeys.
+	Alt string
+}
+
+// ParseTag returns the key name and options of field exactly as Assign
+// interprets them with the default configuration, so that other libraries
+// can follow the same rules. The tag of the first of tagNames present on
+// the field is used, tagNames defaults to "json". The name of skipped
+// fields is empty.
+func ParseTag(field reflect.StructField, tagNames ...string) (name string, opts TagOptions) {
+	if len(tagNames) == 0 {
+		tagNames = []string{defaultAssigner.config.TagName}
+	}
+
+	var tagValue string
+	for _, tagName := range tagNames {
+		if value, ok := field.Tag.Lookup(tagName); ok {
+			tagValue = value
+			break
+		}
+	}
+
+	return defaultAssigner.parseTagValue(field.Name, tagValue)
+}
+
+// hasTag reports whether field has a TagName or TagNames tag.
+func (a *assigner) hasTag(field reflect.StructField) bool {
+	if _, ok := field.Tag.Lookup(a.config.TagName); ok {
+		return true
+	}
+	for _, tagName := range a.config.TagNames {
+		if _, ok := field.Tag.Lookup(tagName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *assigner) parseTag(field reflect.StructField) (actualName string, opts TagOptions) {
+	tagValue, ok := field.Tag.Lookup(a.config.TagName)
+	if !ok {
+		for _, tagName := range a.config.TagNames {
+			if tagValue, ok = field.Tag.Lookup(tagName); ok {
+				break
+			}
+		}
+	}
+	return a.parseTagValue(field.Name, tagValue)
+}
+
+func (a *assigner) parseTagValue(displayName, tagValue string) (actualName string, opts TagOptions) {
 	// Determine the name of the key in the map
 	pieces := strings.Split(tagValue, ",")
 
-	displayName := field.Name
-
-	if len(pieces) == 0 || pieces[0] == "" {
-		actualName = a.config.Converter(displayName)
+	if tagValue != "" && pieces[0] == "" && a.config.UnnamedTag == UnnamedTagFieldName {
+		actualName = displayName
+	} else if pieces[0] == "" {
+		actualName = a.convertName(displayName)
 	} else if pieces[0] == "-" {
 		if a.config.IncludeIgnoreFields {
-			actualName = a.config.Converter(displayName)
+			actualName = a.convertName(displayName)
 		} else {
-			skip = true
+			opts.Skip = true
 		}
 	} else {
 		actualName = pieces[0]
 	}
 
-	for _, piece := range pieces {
-		if piece == "omitempty" {
-			omitempty = true
+	for _, piece := range pieces[1:] {
+		switch piece {
+		case "omitempty":
+			opts.OmitEmpty = true
+		case "omitzero":
+			opts.OmitZero = true
+		case "zero":
+			opts.Zero = true
+		case "squash", "inline":
+			opts.Squash = true
+		case "nosquash":
+			opts.NoSquash = true
+		case "string":
+			opts.String = true
+		default:
+			if strings.HasPrefix(piece, "unit=") {
+				opts.Unit = strings.TrimPrefix(piece, "unit=")
+			} else if strings.HasPrefix(piece, "scale=") {
+				opts.Scale = strings.TrimPrefix(piece, "scale=")
+			} else if strings.HasPrefix(piece, "when=") {
+				opts.When = strings.TrimPrefix(piece, "when=")
+			} else if strings.HasPrefix(piece, "mergekey=") {
+				opts.MergeKey = strings.TrimPrefix(piece, "mergekey=")
+			} else if strings.HasPrefix(piece, "alt=") {
+				opts.Alt = strings.TrimPrefix(piece, "alt=")
+			}
 		}
 	}
 
@@ -1506,8 +3218,12 @@ func genFullKey(parentKind reflect.Kind, parentFull, keyName string) string {
 		return keyName
 	}
 
-	// If parentFull is empty, directly return keyName (regardless of whether keyName is empty)
+	// If parentFull is empty, directly return keyName (regardless of whether keyName is empty),
+	// root slice and array indexes are still bracketed
 	if parentFull == "" {
+		if keyName != "" && (parentKind == reflect.Slice || parentKind == reflect.Array) {
+			return "[" + keyName + "]"
+		}
 		return keyName
 	}
 
@@ -1580,6 +3296,122 @@ func isJsonNumber(typ reflect.Type) bool {
 	return typ.PkgPath() == "encoding/json" && typ.Name() == "Number"
 }
 
+// mapKeyString returns the string form of a map key, unwrapping keys of
+// interface-keyed maps such as map[any]any.
+func mapKeyString(key reflect.Value) string {
+	if key.Kind() == reflect.Interface {
+		key = key.Elem()
+	}
+	if !key.IsValid() {
+		return "<nil>"
+	}
+	if isString(key.Kind()) {
+		return key.String()
+	}
+	return fmt.Sprintf("%v", key.Interface())
+}
+
+// formatNumber formats a numeric value as the shortest decimal text
+// that parses back to the same value.
+func formatNumber(v reflect.Value) (string, bool) {
+	switch kind := v.Kind(); {
+	case isInt(kind):
+		return strconv.FormatInt(v.Int(), 10), true
+	case isUint(kind):
+		return strconv.FormatUint(v.Uint(), 10), true
+	case isFloat(kind):
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), true
+	default:
+		return "", false
+	}
+}
+
+// isNumberText reports whether s is a valid JSON number.
+func isNumberText(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	// Optional -
+	if s[0] == '-' {
+		s = s[1:]
+		if s == "" {
+			return false
+		}
+	}
+
+	// Digits
+	switch {
+	case s[0] == '0':
+		s = s[1:]
+	case '1' <= s[0] && s[0] <= '9':
+		s = s[1:]
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	default:
+		return false
+	}
+
+	// . followed by 1 or more digits.
+	if len(s) >= 2 && s[0] == '.' && '0' <= s[1] && s[1] <= '9' {
+		s = s[2:]
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	}
+
+	// e or E followed by an optional - or + and
+	// 1 or more digits.
+	if len(s) >= 2 && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if s[0] == '+' || s[0] == '-' {
+			s = s[1:]
+			if s == "" {
+				return false
+			}
+		}
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	}
+
+	// Make sure we are at the end.
+	return s == ""
+}
+
+// isEmbeddedStruct reports whether field is an embedded struct or an
+// embedded pointer to a struct.
+func isEmbeddedStruct(field reflect.StructField) bool {
+	if !field.Anonymous {
+		return false
+	}
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType.Kind() == reflect.Struct
+}
+
+// isScalar reports whether kind is a bool, number or string kind.
+func isScalar(kind reflect.Kind) bool {
+	return isBool(kind) || isInt(kind) || isUint(kind) || isFloat(kind) || isString(kind)
+}
+
+// isNilSource reports whether val is an invalid value or a nil pointer,
+// map or slice.
+func isNilSource(val reflect.Value) bool {
+	if !val.IsValid() {
+		return true
+	}
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		return val.IsNil()
+	default:
+		return false
+	}
+}
+
 func isPtrAble(kind reflect.Kind) bool {
 	switch kind {
 	case reflect.Chan, reflect.Func, reflect.Map, reflect.Pointer, reflect.UnsafePointer, reflect.Interface, reflect.Slice:
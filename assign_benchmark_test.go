@@ -2,6 +2,7 @@ package object
 
 import (
 	"encoding/json"
+	"strconv"
 	"testing"
 )
 
@@ -159,6 +160,25 @@ func Benchmark_DecodeMap(b *testing.B) {
 	}
 }
 
+func Benchmark_DecodeMapWithConfig(b *testing.B) {
+	input := map[string]any{
+		"vfoo": "foo",
+		"vother": map[any]any{
+			"foo": "foo",
+			"bar": "bar",
+		},
+	}
+	config := func(c *AssignConfig) {
+		c.SortMetadata = true
+	}
+
+	// Per call configurations build a new assigner on every call
+	var result Map
+	for i := 0; i < b.N; i++ {
+		Assign(&result, input, config)
+	}
+}
+
 func Benchmark_DecodeMapOfStruct(b *testing.B) {
 	input := map[string]any{
 		"value": map[string]any{
@@ -261,3 +281,62 @@ func Benchmark_DecodeTagged(b *testing.B) {
 		Assign(&result, input)
 	}
 }
+
+func largeMapInput() map[string]any {
+	input := make(map[string]any, 10000)
+	for i := 0; i < 10000; i++ {
+		input[strconv.Itoa(i)] = i
+	}
+	return input
+}
+
+func Benchmark_DecodeLargeMap(b *testing.B) {
+	input := largeMapInput()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result := map[string]any{}
+		Assign(&result, input)
+	}
+}
+
+func Benchmark_DecodeLargeStringMap(b *testing.B) {
+	input := make(map[string]string, 10000)
+	for i := 0; i < 10000; i++ {
+		input[strconv.Itoa(i)] = strconv.Itoa(i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result := map[string]string{}
+		Assign(&result, input)
+	}
+}
+
+func Benchmark_DecodeGenerated(b *testing.B) {
+	input := map[string]any{
+		"name":   "Mitchell",
+		"age":    91,
+		"emails": []string{"one", "two", "three"},
+	}
+
+	var result generatedPerson
+	for i := 0; i < b.N; i++ {
+		Assign(&result, input)
+	}
+}
+
+func Benchmark_DecodeSliceRows(b *testing.B) {
+	rows := make([]map[string]any, 100)
+	for i := range rows {
+		rows[i] = map[string]any{
+			"name":   "Mitchell" + strconv.Itoa(i),
+			"age":    i,
+			"emails": []string{"one", "two"},
+		}
+	}
+
+	for i := 0; i < b.N; i++ {
+		DecodeSlice[Person](rows)
+	}
+}
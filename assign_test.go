@@ -1,10 +1,15 @@
 package object
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -2121,6 +2126,2350 @@ func TestDecoder_IncludeIgnoreFields(t *testing.T) {
 	}
 }
 
+func TestAssign_SafeNumerics(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Int8    int8
+		Uint    uint
+		Int     int
+		Float32 float32
+	}
+
+	safe := func(c *AssignConfig) {
+		c.SafeNumerics = true
+	}
+
+	var ok Target
+	err := Assign(&ok, map[string]any{
+		"int8":    int64(127),
+		"uint":    uint16(7),
+		"int":     float64(42),
+		"float32": float64(0.5),
+	}, safe)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ok.Int8 != 127 || ok.Uint != 7 || ok.Int != 42 || ok.Float32 != 0.5 {
+		t.Fatalf("bad: %#v", ok)
+	}
+
+	cases := []struct {
+		name   string
+		target any
+		input  any
+	}{
+		{"int overflow", new(int8), 128},
+		{"negative uint", new(uint), -1},
+		{"uint overflow", new(uint16), uint32(70000)},
+		{"fractional int", new(int), 1.5},
+		{"float32 precision", new(float32), 0.1},
+		{"json number overflow", new(int8), json.Number("300")},
+	}
+
+	for _, tc := range cases {
+		err := Assign(tc.target, tc.input, safe)
+
+		var rangeErr *RangeError
+		if !errors.As(err, &rangeErr) {
+			t.Fatalf("%s: expected *RangeError, got: %v", tc.name, err)
+		}
+	}
+
+	var result Target
+	err = Assign(&result, map[string]any{"int8": 300}, safe)
+	if err == nil || !strings.Contains(err.Error(), "'Int8' value 300 is out of range for type 'int8'") {
+		t.Fatalf("expected range error with path, got: %v", err)
+	}
+	var rangeErr *RangeError
+	if !errors.As(err, &rangeErr) || rangeErr.Path != "Int8" {
+		t.Fatalf("expected the path of the range error, got: %#v", rangeErr)
+	}
+
+	// Without SafeNumerics values are silently narrowed
+	var narrowed Target
+	if err := Assign(&narrowed, map[string]any{"int8": 128}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if narrowed.Int8 != -128 {
+		t.Fatalf("bad: %#v", narrowed)
+	}
+}
+
+func TestAssign_NumberStrings(t *testing.T) {
+	t.Parallel()
+
+	type Price struct {
+		Amount   string
+		Rate     string
+		Total    json.Number
+		Quantity int
+		Discount float64
+	}
+
+	input := map[string]any{
+		"amount":   json.Number("12345678901234567890.123456789"),
+		"rate":     float32(0.1),
+		"total":    1234.5,
+		"quantity": "42",
+		"discount": "0.25",
+	}
+
+	var result Price
+	err := Assign(&result, input, func(c *AssignConfig) {
+		c.NumberStrings = true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Price{
+		Amount:   "12345678901234567890.123456789",
+		Rate:     "0.1",
+		Total:    "1234.5",
+		Quantity: 42,
+		Discount: 0.25,
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, result)
+	}
+
+	var number json.Number
+	err = Assign(&number, "12.5.3", func(c *AssignConfig) {
+		c.NumberStrings = true
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid number text")
+	}
+
+	var str string
+	if err := Assign(&str, 1.5); err == nil {
+		t.Fatal("expected error without NumberStrings")
+	}
+}
+
+func TestAssign_UnnamedTag(t *testing.T) {
+	t.Parallel()
+
+	type Input struct {
+		UserName string `json:",omitempty"`
+		FullName string
+	}
+
+	input := Input{UserName: "gopher", FullName: "Go Gopher"}
+
+	actual := map[string]any{}
+	if err := Assign(&actual, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]any{
+		"userName": "gopher",
+		"fullName": "Go Gopher",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, actual)
+	}
+
+	fieldName := func(c *AssignConfig) {
+		c.UnnamedTag = UnnamedTagFieldName
+	}
+
+	actual = map[string]any{}
+	if err := Assign(&actual, input, fieldName); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected = map[string]any{
+		"UserName": "gopher",
+		"fullName": "Go Gopher",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, actual)
+	}
+}
+
+func TestAssign_OmitZeroStructs(t *testing.T) {
+	t.Parallel()
+
+	type Address struct {
+		City string
+	}
+
+	type Person struct {
+		Name    string  `json:"name,omitempty"`
+		Address Address `json:"address,omitempty"`
+	}
+
+	actual := map[string]any{}
+	if err := Assign(&actual, Person{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Like encoding/json, structs are never empty
+	expected := map[string]any{
+		"address": Address{},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, actual)
+	}
+
+	actual = map[string]any{}
+	if err := Assign(&actual, Person{}, func(c *AssignConfig) {
+		c.OmitZeroStructs = true
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(actual) != 0 {
+		t.Fatalf("expected empty map, got: %#v", actual)
+	}
+
+	// omitempty only applies to sources, empty target fields are still assigned
+	var result Person
+	err := Assign(&result, map[string]any{
+		"name":    "gopher",
+		"address": map[string]any{"city": "Berlin"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Name != "gopher" || result.Address.City != "Berlin" {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestIsZeroIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	type Point struct {
+		X, Y int
+	}
+
+	cases := []struct {
+		value any
+		zero  bool
+		empty bool
+	}{
+		{nil, true, true},
+		{0, true, true},
+		{"", true, true},
+		{"x", false, false},
+		{[]int{}, false, true},
+		{[]int(nil), true, true},
+		{map[string]any{}, false, true},
+		{[2]int{}, true, false},
+		{Point{}, true, false},
+		{Point{X: 1}, false, false},
+		{(*Point)(nil), true, true},
+	}
+
+	for _, tc := range cases {
+		if zero := IsZero(tc.value); zero != tc.zero {
+			t.Errorf("IsZero(%#v) = %v, expected %v", tc.value, zero, tc.zero)
+		}
+		if empty := IsEmpty(tc.value); empty != tc.empty {
+			t.Errorf("IsEmpty(%#v) = %v, expected %v", tc.value, empty, tc.empty)
+		}
+	}
+}
+
+func TestAssign_PointerToInterface(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Data   *any
+		Reader *io.Reader
+	}
+
+	// Nil pointers are allocated and receive the concrete value
+	buf := bytes.NewBufferString("first")
+	var result Target
+	err := Assign(&result, map[string]any{
+		"data":   42,
+		"reader": buf,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Data == nil || *result.Data != 42 {
+		t.Fatalf("bad data: %#v", result.Data)
+	}
+	if result.Reader == nil || *result.Reader != io.Reader(buf) {
+		t.Fatalf("bad reader: %#v", result.Reader)
+	}
+
+	// A different implementation replaces the held value
+	replacement := strings.NewReader("second")
+	if err := Assign(&result, map[string]any{"reader": replacement}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if *result.Reader != io.Reader(replacement) {
+		t.Fatalf("bad reader: %#v", *result.Reader)
+	}
+
+	// Values that don't implement the interface are rejected
+	if err := Assign(&result, map[string]any{"reader": "text"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// Values held by empty interfaces are merged into
+	var data any = &Basic{Vstring: "foo"}
+	result.Data = &data
+	if err := Assign(&result, map[string]any{"data": map[string]any{"vint": 7}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	basic, ok := (*result.Data).(*Basic)
+	if !ok || basic.Vstring != "foo" || basic.Vint != 7 {
+		t.Fatalf("bad data: %#v", *result.Data)
+	}
+
+	// Nil sources reset the pointer
+	if err := Assign(&result, map[string]any{"data": nil, "reader": (*bytes.Buffer)(nil)}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Reader != nil {
+		t.Fatalf("expected nil reader, got: %#v", result.Reader)
+	}
+}
+
+func TestAssign_MixedSliceElements(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		Name  string
+		Count int
+	}
+
+	input := []any{
+		map[string]any{"name": "map"},
+		Item{Name: "struct"},
+		&Item{Name: "pointer"},
+		nil,
+	}
+
+	var items []Item
+	if err := Assign(&items, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []Item{{Name: "map"}, {Name: "struct"}, {Name: "pointer"}, {}}
+	if !reflect.DeepEqual(items, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, items)
+	}
+
+	var pointers []*Item
+	if err := Assign(&pointers, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(pointers) != 4 || pointers[0].Name != "map" || pointers[2].Name != "pointer" || pointers[3] != nil {
+		t.Fatalf("bad: %#v", pointers)
+	}
+
+	var invalid []Item
+	err := Assign(&invalid, []any{Item{}, 5, map[string]any{"count": "x"}})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	derr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error, got: %T", err)
+	}
+
+	sort.Strings(derr.Errors)
+	expectedErrors := []string{
+		"'[1]' expected a map or struct, got 'int'",
+		"'[2].Count' expected type 'int', got unconvertible type 'string', value: 'x'",
+	}
+	if !reflect.DeepEqual(derr.Errors, expectedErrors) {
+		t.Fatalf("expected: %#v, got: %#v", expectedErrors, derr.Errors)
+	}
+}
+
+func TestAssign_CollectionErrors(t *testing.T) {
+	t.Parallel()
+
+	type Row struct {
+		Name string
+		Age  int
+	}
+
+	type Table struct {
+		Rows   []Row
+		Limits map[string]int
+	}
+
+	input := map[string]any{
+		"rows": []any{
+			map[string]any{"name": "ok", "age": 1},
+			map[string]any{"name": "bad", "age": "x"},
+			map[string]any{"name": "ok", "age": 3},
+			map[string]any{"name": "bad", "age": "y"},
+		},
+		"limits": map[string]any{"cpu": 2, "memory": "lots"},
+	}
+
+	var result Table
+	err := Assign(&result, input)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var derr *Error
+	if !errors.As(err, &derr) {
+		t.Fatalf("expected *Error, got: %T", err)
+	}
+
+	var rows []int
+	var keys []string
+	for _, ce := range derr.Collection {
+		if ce.Key != "" {
+			keys = append(keys, ce.Key)
+			if ce.Index != -1 || ce.Path != "Limits["+ce.Key+"]" {
+				t.Fatalf("bad map element error: %#v", ce)
+			}
+			continue
+		}
+		rows = append(rows, ce.Index)
+		if ce.Path != "Rows["+strconv.Itoa(ce.Index)+"]" {
+			t.Fatalf("bad slice element error: %#v", ce)
+		}
+	}
+
+	if !reflect.DeepEqual(rows, []int{1, 3}) {
+		t.Fatalf("bad rows: %#v", rows)
+	}
+	if !reflect.DeepEqual(keys, []string{"memory"}) {
+		t.Fatalf("bad keys: %#v", keys)
+	}
+}
+
+func TestAssign_Settability(t *testing.T) {
+	t.Parallel()
+
+	// Map values of struct type are merged through copies
+	structs := map[string]Basic{
+		"a": {Vstring: "keep", Vint: 1},
+	}
+	err := Assign(&structs, map[string]any{
+		"a": map[string]any{"vint": 2},
+		"b": map[string]any{"vstring": "new"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if structs["a"].Vstring != "keep" || structs["a"].Vint != 2 || structs["b"].Vstring != "new" {
+		t.Fatalf("bad: %#v", structs)
+	}
+
+	// Array elements of interface type holding struct values
+	array := [2]any{Basic{Vstring: "first"}, &Basic{Vstring: "second"}}
+	err = Assign(&array, []any{
+		map[string]any{"vint": 1},
+		map[string]any{"vint": 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	first, ok := array[0].(Basic)
+	if !ok || first.Vstring != "first" || first.Vint != 1 {
+		t.Fatalf("bad first element: %#v", array[0])
+	}
+	second, ok := array[1].(*Basic)
+	if !ok || second.Vstring != "second" || second.Vint != 2 {
+		t.Fatalf("bad second element: %#v", array[1])
+	}
+
+	// Values of map[string]any are replaced, whatever their type
+	nested := map[string]any{
+		"outer": map[string]any{
+			"inner": Basic{Vstring: "deep"},
+			"other": 1,
+		},
+		"count": 1,
+	}
+	err = Assign(&nested, map[string]any{
+		"outer": map[string]any{
+			"inner": map[string]any{"vbool": true},
+		},
+		"count": "one",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]any{
+		"outer": map[string]any{
+			"inner": map[string]any{"vbool": true},
+		},
+		"count": "one",
+	}
+	if !reflect.DeepEqual(nested, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, nested)
+	}
+}
+
+type getterSource struct {
+	name  string
+	count int
+}
+
+func (s *getterSource) GetName() string { return s.name }
+
+func (s getterSource) GetCount() int { return s.count }
+
+func (s *getterSource) GetInvalid(prefix string) string { return prefix }
+
+func TestAssign_UseGetters(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Name    string
+		Count   int
+		Invalid string
+	}
+
+	source := getterSource{name: "gopher", count: 3}
+
+	var result Target
+	if err := Assign(&result, source); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != (Target{}) {
+		t.Fatalf("getters must not be used by default: %#v", result)
+	}
+
+	var md Metadata
+	err := Assign(&result, source, func(c *AssignConfig) {
+		c.UseGetters = true
+		c.Metadata = &md
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Target{Name: "gopher", Count: 3}
+	if result != expected {
+		t.Fatalf("expected: %#v, got: %#v", expected, result)
+	}
+	if !reflect.DeepEqual(md.Unset, []string{"Invalid"}) {
+		t.Fatalf("bad unset: %#v", md.Unset)
+	}
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	t.Parallel()
+
+	input := map[any]any{
+		"name": "gopher",
+		1:      "one",
+		"nested": map[any]any{
+			"list": []any{map[any]any{true: "yes"}},
+		},
+		"typed": map[string]int{"a": 1},
+	}
+
+	expected := map[string]any{
+		"name": "gopher",
+		"1":    "one",
+		"nested": map[string]any{
+			"list": []any{map[string]any{"true": "yes"}},
+		},
+		"typed": map[string]int{"a": 1},
+	}
+
+	if actual := NormalizeKeys(input); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, actual)
+	}
+
+	// Typed maps and slices holding interface keyed maps are normalized too.
+	nested := map[string]map[any]any{"db": {"port": 5432}}
+	expectedNested := map[string]any{"db": map[string]any{"port": 5432}}
+	if actual := NormalizeKeys(nested); !reflect.DeepEqual(actual, expectedNested) {
+		t.Fatalf("expected: %#v, got: %#v", expectedNested, actual)
+	}
+
+	list := []map[any]any{{1: "one"}}
+	expectedList := []any{map[string]any{"1": "one"}}
+	if actual := NormalizeKeys(list); !reflect.DeepEqual(actual, expectedList) {
+		t.Fatalf("expected: %#v, got: %#v", expectedList, actual)
+	}
+
+	plain := [][]string{{"a"}}
+	if actual := NormalizeKeys(plain); !reflect.DeepEqual(actual, plain) {
+		t.Fatalf("expected: %#v, got: %#v", plain, actual)
+	}
+}
+
+func TestAssign_InterfaceKeyedMaps(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Name   string
+		Limits map[int]string
+	}
+
+	input := map[any]any{
+		"name":   "gopher",
+		"limits": map[any]any{1: "one", "2": "two"},
+		"extra":  true,
+	}
+
+	var md Metadata
+	var result Target
+	if err := Assign(&result, input, func(c *AssignConfig) {
+		c.Metadata = &md
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Target{
+		Name:   "gopher",
+		Limits: map[int]string{1: "one", 2: "two"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, result)
+	}
+	if !reflect.DeepEqual(md.Unused, []string{"extra"}) {
+		t.Fatalf("bad unused: %#v", md.Unused)
+	}
+}
+
+func TestAssign_NilSourcePolicy(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Tags    []string
+		Labels  map[string]string
+		Pointer *string
+		Name    string
+	}
+
+	newTarget := func() Target {
+		return Target{
+			Tags:    []string{"a"},
+			Labels:  map[string]string{"k": "v"},
+			Pointer: stringPtr("p"),
+			Name:    "name",
+		}
+	}
+
+	type Pointers struct {
+		Typed   *string
+		Untyped *string
+	}
+
+	input := map[string]any{
+		"tags":    []string(nil),
+		"labels":  map[string]string(nil),
+		"pointer": (*string)(nil),
+		"name":    nil,
+	}
+
+	withPolicy := func(policy NilSourcePolicy) func(c *AssignConfig) {
+		return func(c *AssignConfig) {
+			c.NilSource = policy
+		}
+	}
+
+	result := newTarget()
+	if err := Assign(&result, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := newTarget()
+	expected.Pointer = nil
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("default: expected: %#v, got: %#v", expected, result)
+	}
+
+	// By default typed nil pointers clear pointers, untyped nils don't
+	pointers := Pointers{Typed: stringPtr("a"), Untyped: stringPtr("b")}
+	if err := Assign(&pointers, map[string]any{"typed": (*string)(nil), "untyped": nil}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if pointers.Typed != nil || pointers.Untyped == nil || *pointers.Untyped != "b" {
+		t.Fatalf("default: bad pointers: %#v", pointers)
+	}
+
+	pointers = Pointers{Typed: stringPtr("a"), Untyped: stringPtr("b")}
+	if err := Assign(&pointers, map[string]any{"typed": (*string)(nil), "untyped": nil}, withPolicy(NilSourceClear)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if pointers != (Pointers{}) {
+		t.Fatalf("clear: bad pointers: %#v", pointers)
+	}
+
+	result = newTarget()
+	if err := Assign(&result, input, withPolicy(NilSourceKeep)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(result, newTarget()) {
+		t.Fatalf("keep: expected: %#v, got: %#v", newTarget(), result)
+	}
+
+	result = newTarget()
+	if err := Assign(&result, input, withPolicy(NilSourceClear)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(result, Target{}) {
+		t.Fatalf("clear: expected: %#v, got: %#v", Target{}, result)
+	}
+
+	result = newTarget()
+	err := Assign(&result, map[string]any{"tags": []string(nil)}, withPolicy(NilSourceError))
+	if err == nil || !strings.Contains(err.Error(), "'Tags' expected a value, got nil") {
+		t.Fatalf("error: unexpected error: %v", err)
+	}
+}
+
+func TestAssign_ZeroTag(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Merged   map[string]string
+		Replaced map[string]string `json:"replaced,zero"`
+		Items    []string          `json:"items,zero"`
+		Kept     []string          `json:"kept,zero"`
+	}
+
+	result := Config{
+		Merged:   map[string]string{"a": "1"},
+		Replaced: map[string]string{"a": "1"},
+		Items:    []string{"x", "y", "z"},
+		Kept:     []string{"k"},
+	}
+
+	input := map[string]any{
+		"merged":   map[string]string{"b": "2"},
+		"replaced": map[string]string{"b": "2"},
+		"items":    []string{"w"},
+	}
+
+	if err := Assign(&result, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Config{
+		Merged:   map[string]string{"a": "1", "b": "2"},
+		Replaced: map[string]string{"b": "2"},
+		Items:    []string{"w"},
+		Kept:     []string{"k"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, result)
+	}
+}
+
+func TestAssign_BoolStrings(t *testing.T) {
+	t.Parallel()
+
+	type Flags struct {
+		A bool
+		B bool
+		C bool
+	}
+
+	var result Flags
+	if err := Assign(&result, map[string]any{"a": "true"}); err == nil {
+		t.Fatal("expected error without BoolStrings")
+	}
+
+	err := Assign(&result, map[string]any{"a": " TRUE ", "b": "1", "c": "False"}, func(c *AssignConfig) {
+		c.BoolStrings = true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != (Flags{A: true, B: true}) {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	vocabulary := func(c *AssignConfig) {
+		c.BoolStrings = true
+		c.TrueStrings = []string{"yes", "on"}
+		c.FalseStrings = []string{"no", "off"}
+	}
+
+	result = Flags{}
+	err = Assign(&result, map[string]any{"a": "Yes", "b": " on", "c": "OFF"}, vocabulary)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != (Flags{A: true, B: true}) {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	if err := Assign(&result, map[string]any{"a": "true"}, vocabulary); err == nil {
+		t.Fatal("expected error for word outside the vocabulary")
+	}
+
+	// BoolStrings doesn't enable other weak conversions
+	if err := Assign(&result, map[string]any{"a": 1}, vocabulary); err == nil {
+		t.Fatal("expected error for int source")
+	}
+}
+
+func TestAssign_LargeMapFastPath(t *testing.T) {
+	t.Parallel()
+
+	type Limit int
+
+	input := map[string]Limit{"cpu": 2, "memory": 512}
+
+	var md Metadata
+	result := map[string]Limit{"disk": 10}
+	if err := Assign(&result, input, func(c *AssignConfig) {
+		c.Metadata = &md
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]Limit{"cpu": 2, "memory": 512, "disk": 10}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, result)
+	}
+
+	sort.Strings(md.Keys)
+	if !reflect.DeepEqual(md.Keys, []string{"cpu", "memory"}) {
+		t.Fatalf("bad keys: %#v", md.Keys)
+	}
+
+	generic := map[string]any{}
+	if err := Assign(&generic, map[string]any{"a": 1, "b": []string(nil)}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v, ok := generic["b"]; !ok || v != nil {
+		t.Fatalf("nil values must follow the general path: %#v", generic)
+	}
+
+	// Only options known not to change map entries keep the fast path.
+	if !defaultAssigner.fastMaps || !defaultAssigner.keyAssigner.fastMaps {
+		t.Fatal("expected the default configuration to use the fast path")
+	}
+	for _, config := range []func(c *AssignConfig){
+		func(c *AssignConfig) { c.Metadata = &md; c.WeaklyTypedInput = true; c.TagName = "yaml" },
+		func(c *AssignConfig) { c.FailFast = true; c.Converter = strings.ToUpper },
+	} {
+		if !defaultAssigner.withConfig(config).fastMaps {
+			t.Fatal("expected the fast path")
+		}
+	}
+	for _, config := range []func(c *AssignConfig){
+		func(c *AssignConfig) { c.Wrappers = true },
+		func(c *AssignConfig) { c.CopyBytes = true },
+		func(c *AssignConfig) { c.SkipKeys = []string{"a"} },
+		func(c *AssignConfig) { c.Hook = func(_, _ reflect.Type, data any) (any, error) { return data, nil } },
+	} {
+		if defaultAssigner.withConfig(config).fastMaps {
+			t.Fatal("expected the general path")
+		}
+	}
+}
+
+func TestAssign_MapKeysUseConfig(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]string{
+		"yes": "enabled",
+		"off": "disabled",
+	}
+
+	var result map[bool]string
+	if err := Assign(&result, input); err == nil {
+		t.Fatal("expected error with default vocabulary")
+	}
+
+	result = nil
+	err := Assign(&result, input, func(c *AssignConfig) {
+		c.TrueStrings = []string{"yes", "on"}
+		c.FalseStrings = []string{"no", "off"}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[bool]string{true: "enabled", false: "disabled"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, result)
+	}
+}
+
+func TestAssign_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	source := &EmbeddedPointer{Vunique: "unique"}
+	input := map[string]any{
+		"vstring": "foo",
+		"vint":    42,
+		"vextra":  "skipped",
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var fromMap Basic
+			if err := Assign(&fromMap, input, func(c *AssignConfig) {
+				c.SkipKeys = []string{"Vextra"}
+			}); err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			if fromMap.Vstring != "foo" || fromMap.Vint != 42 || fromMap.Vextra != "" {
+				t.Errorf("bad: %#v", fromMap)
+			}
+
+			fromStruct := map[string]any{}
+			if err := Assign(&fromStruct, source); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if source.Basic != nil {
+		t.Fatalf("source must not be modified: %#v", source.Basic)
+	}
+}
+
+type promotedBase struct {
+	ID   int
+	Name string
+	note string
+}
+
+type promotedOuter struct {
+	promotedBase
+	*Basic
+	Extra string
+}
+
+func TestAssign_PromoteUnexportedEmbedded(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]any{
+		"id":    7,
+		"name":  "gopher",
+		"extra": "more",
+	}
+
+	var result promotedOuter
+	if err := Assign(&result, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.ID != 0 || result.Name != "" || result.Extra != "more" {
+		t.Fatalf("unexported embedded structs must be ignored by default: %#v", result)
+	}
+
+	promote := func(c *AssignConfig) {
+		c.PromoteUnexportedEmbedded = true
+	}
+
+	result = promotedOuter{}
+	if err := Assign(&result, input, promote); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.ID != 7 || result.Name != "gopher" || result.Extra != "more" {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	actual := map[string]any{}
+	source := promotedOuter{promotedBase: promotedBase{ID: 1, Name: "n", note: "hidden"}}
+	if err := Assign(&actual, source, promote); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual["id"] != 1 || actual["name"] != "n" {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if _, exist := actual["note"]; exist {
+		t.Fatalf("unexported fields must not be promoted: %#v", actual)
+	}
+}
+
+func TestAssign_SquashInline(t *testing.T) {
+	t.Parallel()
+
+	type Inner struct {
+		City string `yaml:"city"`
+	}
+	type Outer struct {
+		Name    string `yaml:"name"`
+		Address Inner  `yaml:",inline"`
+		Extra   *Inner `yaml:",squash"`
+	}
+
+	yamlTag := func(c *AssignConfig) {
+		c.TagName = "yaml"
+	}
+
+	var result Outer
+	if err := Assign(&result, map[string]any{"name": "n", "city": "c"}, yamlTag); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Name != "n" || result.Address.City != "c" {
+		t.Fatalf("bad: %#v", result)
+	}
+	if result.Extra == nil || result.Extra.City != "" {
+		t.Fatalf("duplicated squashed keys must keep the first field: %#v", result.Extra)
+	}
+
+	actual := map[string]any{}
+	if err := Assign(&actual, Outer{Name: "n", Address: Inner{City: "c"}}, yamlTag); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]any{"name": "n", "city": "c"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestAssign_EmptyStructAsNil(t *testing.T) {
+	t.Parallel()
+
+	type Empty struct {
+		Name string `json:",omitempty"`
+	}
+	type Outer struct {
+		Inner Empty
+	}
+
+	var actual map[string]any
+	if err := Assign(&actual, Empty{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual == nil || len(actual) != 0 {
+		t.Fatalf("expected an empty map by default, got %#v", actual)
+	}
+
+	emptyAsNil := func(c *AssignConfig) {
+		c.EmptyStructAsNil = true
+	}
+
+	actual = nil
+	if err := Assign(&actual, Empty{}, emptyAsNil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual != nil {
+		t.Fatalf("expected nil map, got %#v", actual)
+	}
+
+	var nested struct {
+		Inner map[string]any
+	}
+	if err := Assign(&nested, Outer{}, emptyAsNil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if nested.Inner != nil {
+		t.Fatalf("expected nil nested map, got %#v", nested.Inner)
+	}
+
+	actual = nil
+	if err := Assign(&actual, Empty{Name: "n"}, emptyAsNil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual["name"] != "n" {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+type panicDecimal struct{}
+
+func (d *panicDecimal) SetString(s string) error {
+	panic("bad decimal " + s)
+}
+
+func (d panicDecimal) String() string {
+	return ""
+}
+
+func TestAssign_Recover(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Items []struct {
+			Amount panicDecimal
+		}
+	}
+
+	input := map[string]any{
+		"items": []map[string]any{{"amount": "1.5"}},
+	}
+
+	var result Target
+	err := Assign(&result, input, func(c *AssignConfig) {
+		c.Recover = true
+	})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	if !strings.Contains(err.Error(), "'Items[0].Amount' panic: bad decimal 1.5") {
+		t.Fatalf("bad error: %s", err)
+	}
+
+	var dec panicDecimal
+	err = Assign(&dec, "2", func(c *AssignConfig) {
+		c.Recover = true
+	})
+	var perr *PanicError
+	if !errors.As(err, &perr) || perr.Value != "bad decimal 2" || perr.Path != "" {
+		t.Fatalf("expected a PanicError, got %#v", err)
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	t.Parallel()
+
+	type Tagged struct {
+		Plain     string
+		Renamed   string `json:"renamed_field,omitempty"`
+		Ignored   string `json:"-"`
+		Squashed  Basic  `json:",squash,zero"`
+		YAMLFirst string `yaml:"yaml_name" json:"json_name"`
+	}
+
+	typ := reflect.TypeOf(Tagged{})
+	cases := []struct {
+		field    string
+		tagNames []string
+		name     string
+		opts     TagOptions
+	}{
+		{"Plain", nil, "plain", TagOptions{}},
+		{"Renamed", nil, "renamed_field", TagOptions{OmitEmpty: true}},
+		{"Ignored", nil, "", TagOptions{Skip: true}},
+		{"Squashed", nil, "squashed", TagOptions{Squash: true, Zero: true}},
+		{"YAMLFirst", nil, "json_name", TagOptions{}},
+		{"YAMLFirst", []string{"yaml", "json"}, "yaml_name", TagOptions{}},
+		{"Renamed", []string{"yaml", "json"}, "renamed_field", TagOptions{OmitEmpty: true}},
+	}
+
+	for _, tc := range cases {
+		field, _ := typ.FieldByName(tc.field)
+		name, opts := ParseTag(field, tc.tagNames...)
+		if name != tc.name || opts != tc.opts {
+			t.Errorf("%s %v: got %q %#v, expected %q %#v", tc.field, tc.tagNames, name, opts, tc.name, tc.opts)
+		}
+	}
+}
+
+func TestAssign_IntegerFormats(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Int  int
+		Uint uint
+	}
+
+	cases := []struct {
+		formats IntegerFormat
+		input   string
+		value   int
+		err     string
+	}{
+		{0, "0x1F", 31, ""},
+		{0, "010", 8, ""},
+		{0, "0b11", 3, ""},
+		{0, "+7", 7, ""},
+		{IntegerDecimal, "-42", -42, ""},
+		{IntegerDecimal, "+42", 42, ""},
+		{IntegerDecimal, "010", 10, ""},
+		{IntegerDecimal, "0x1F", 0, "'Int' as int: hexadecimal integer '0x1F' is not allowed"},
+		{IntegerDecimal, "0o17", 0, "'Int' as int: octal integer '0o17' is not allowed"},
+		{IntegerDecimal, "-0b1", 0, "'Int' as int: binary integer '-0b1' is not allowed"},
+		{IntegerDecimal, "1_000", 0, "invalid syntax"},
+		{IntegerDecimal | IntegerHex, "0X1f", 31, ""},
+		{IntegerDecimal | IntegerOctal, "010", 8, ""},
+		{IntegerAll, "1_000", 1000, ""},
+	}
+
+	for _, tc := range cases {
+		var result Target
+		err := Assign(&result, map[string]any{"int": tc.input}, func(c *AssignConfig) {
+			c.WeaklyTypedInput = true
+			c.IntegerFormats = tc.formats
+		})
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%d %q: expected error %q, got %v", tc.formats, tc.input, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d %q: unexpected error: %s", tc.formats, tc.input, err)
+			continue
+		}
+		if result.Int != tc.value {
+			t.Errorf("%d %q: expected %d, got %d", tc.formats, tc.input, tc.value, result.Int)
+		}
+	}
+
+	var result Target
+	err := Assign(&result, map[string]any{"uint": "+0x10"}, func(c *AssignConfig) {
+		c.WeaklyTypedInput = true
+	})
+	if err != nil || result.Uint != 16 {
+		t.Fatalf("expected 16, got %d, %v", result.Uint, err)
+	}
+
+	err = Assign(&result, map[string]any{"uint": "0x10"}, func(c *AssignConfig) {
+		c.WeaklyTypedInput = true
+		c.IntegerFormats = IntegerDecimal
+	})
+	if err == nil || !strings.Contains(err.Error(), "'Uint' as uint: hexadecimal") {
+		t.Fatalf("expected a format error, got %v", err)
+	}
+}
+
+func TestAssign_TruncateArrays(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Values [2]int
+	}
+
+	input := map[string]any{"values": [4]int{1, 2, 3, 4}}
+
+	var result Target
+	if err := Assign(&result, input); err == nil {
+		t.Fatalf("expected a length error by default")
+	}
+
+	var md Metadata
+	result = Target{}
+	err := Assign(&result, input, func(c *AssignConfig) {
+		c.TruncateArrays = true
+		c.Metadata = &md
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Values != [2]int{1, 2} {
+		t.Fatalf("bad: %#v", result.Values)
+	}
+
+	expected := []string{"'Values': 2 source element(s) truncated to fit length 2"}
+	if !reflect.DeepEqual(md.Warnings, expected) {
+		t.Fatalf("expected warnings %#v, got %#v", expected, md.Warnings)
+	}
+	if !reflect.DeepEqual(md.Keys, []string{"Values[0]", "Values[1]", "Values"}) {
+		t.Fatalf("bad keys: %#v", md.Keys)
+	}
+}
+
+func TestAssign_CopyBytes(t *testing.T) {
+	t.Parallel()
+
+	type Source struct {
+		Data []byte
+	}
+
+	copyBytes := func(c *AssignConfig) {
+		c.CopyBytes = true
+	}
+
+	source := Source{Data: []byte("abc")}
+
+	shared := map[string]any{}
+	if err := Assign(&shared, source); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	copied := map[string]any{}
+	if err := Assign(&copied, source, copyBytes); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var iface any
+	if err := Assign(&iface, source.Data, copyBytes); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	fromMap := map[string]any{}
+	if err := Assign(&fromMap, map[string]any{"data": source.Data}, copyBytes); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	source.Data[0] = 'x'
+
+	if string(shared["data"].([]byte)) != "xbc" {
+		t.Fatalf("expected byte slices to be shared by default")
+	}
+	if string(copied["data"].([]byte)) != "abc" {
+		t.Fatalf("expected a copy, got %q", copied["data"])
+	}
+	if string(iface.([]byte)) != "abc" {
+		t.Fatalf("expected a copy, got %q", iface)
+	}
+	if string(fromMap["data"].([]byte)) != "abc" {
+		t.Fatalf("expected a copy, got %q", fromMap["data"])
+	}
+}
+
+func TestAssign_DeepInterfaceMaps(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		Name string
+	}
+	type Source struct {
+		Item    Item
+		Ptr     *Item
+		Nil     *Item
+		Items   []Item
+		ByName  map[string]*Item
+		Any     any
+		Tags    []string
+		Created time.Time
+	}
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	source := Source{
+		Item:    Item{Name: "a"},
+		Ptr:     &Item{Name: "b"},
+		Items:   []Item{{Name: "c"}},
+		ByName:  map[string]*Item{"d": {Name: "d"}},
+		Any:     Item{Name: "e"},
+		Tags:    []string{"x"},
+		Created: created,
+	}
+
+	shallow := map[string]any{}
+	if err := Assign(&shallow, source); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := shallow["item"].(Item); !ok {
+		t.Fatalf("expected nested structs to be kept by default, got %#v", shallow["item"])
+	}
+
+	actual := map[string]any{}
+	if err := Assign(&actual, source, func(c *AssignConfig) {
+		c.DeepInterfaceMaps = true
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]any{
+		"item":    map[string]any{"name": "a"},
+		"ptr":     map[string]any{"name": "b"},
+		"nil":     nil,
+		"items":   []any{map[string]any{"name": "c"}},
+		"byName":  map[string]any{"d": map[string]any{"name": "d"}},
+		"any":     map[string]any{"name": "e"},
+		"tags":    []string{"x"},
+		"created": created,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestAssign_TagNames(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Both   string `object:"both_object" json:"both_json"`
+		JSON   string `json:"json_only"`
+		YAML   string `yaml:"yaml_only"`
+		Ignore string `json:"-"`
+		Plain  string
+	}
+
+	input := map[string]any{
+		"both_object": "1",
+		"both_json":   "wrong",
+		"json_only":   "2",
+		"yaml_only":   "3",
+		"ignore":      "4",
+		"plain":       "5",
+	}
+
+	var result Target
+	if err := Assign(&result, input, func(c *AssignConfig) {
+		c.TagName = "object"
+		c.TagNames = []string{"json", "yaml"}
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Target{Both: "1", JSON: "2", YAML: "3", Plain: "5"}
+	if result != expected {
+		t.Fatalf("expected %#v, got %#v", expected, result)
+	}
+
+	result = Target{}
+	if err := Assign(&result, input, func(c *AssignConfig) {
+		c.TagName = "object"
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.JSON != "" || result.Ignore != "4" {
+		t.Fatalf("without TagNames other tags must be ignored: %#v", result)
+	}
+}
+
+type squashLeft struct {
+	ID   int
+	Name string
+}
+
+type squashRight struct {
+	ID    int `json:"id"`
+	Label string
+}
+
+type squashCollision struct {
+	squashLeft  `json:",squash"`
+	squashRight `json:",squash"`
+}
+
+type squashShadowed struct {
+	squashLeft
+	ID int
+}
+
+func TestAssign_SquashCollisions(t *testing.T) {
+	t.Parallel()
+
+	promote := func(c *AssignConfig) {
+		c.PromoteUnexportedEmbedded = true
+	}
+	strict := func(c *AssignConfig) {
+		c.PromoteUnexportedEmbedded = true
+		c.SquashCollisions = SquashCollisionError
+	}
+
+	input := map[string]any{"id": 1, "name": "n", "label": "l"}
+
+	var result squashCollision
+	if err := Assign(&result, input, promote); err != nil {
+		t.Fatalf("unexpected error by default: %s", err)
+	}
+
+	result = squashCollision{}
+	err := Assign(&result, input, strict)
+	if err == nil {
+		t.Fatalf("expected a collision error")
+	}
+	expected := "'' ambiguous squashed key(s): 'id' (squashCollision.squashLeft.ID, squashCollision.squashRight.ID)"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+
+	var actual map[string]any
+	if err := Assign(&actual, squashCollision{}, strict); err == nil {
+		t.Fatalf("expected a collision error for struct to map")
+	}
+
+	// Shallower fields shadow deeper ones without ambiguity.
+	var shadowed squashShadowed
+	if err := Assign(&shadowed, input, strict); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if shadowed.ID != 1 || shadowed.Name != "n" {
+		t.Fatalf("bad: %#v", shadowed)
+	}
+
+	// A type embedded twice at the same depth is ambiguous too.
+	type A struct {
+		X int
+	}
+	type P struct{ A }
+	type Q struct{ A }
+	type Outer struct {
+		P
+		Q
+	}
+	actual = nil
+	err = Assign(&actual, Outer{P{A{1}}, Q{A{2}}}, strict)
+	expected = "'' ambiguous squashed key(s): 'x' (Outer.P.A.X, Outer.Q.A.X)"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestAssign_EmbeddedAliases(t *testing.T) {
+	t.Parallel()
+
+	type Untagged struct {
+		SliceAlias
+		*ArrayAlias
+	}
+
+	sliceSource := EmbeddedSlice{SliceAlias: SliceAlias{"foo", "bar"}, Vunique: "u"}
+	arraySource := EmbeddedArray{ArrayAlias: ArrayAlias{"foo", "bar"}, Vunique: "u"}
+
+	actual := map[string]any{}
+	if err := Assign(&actual, sliceSource); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(actual["slice_alias"], SliceAlias{"foo", "bar"}) || actual["vunique"] != "u" {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	actual = map[string]any{}
+	if err := Assign(&actual, arraySource); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual["array_alias"] != (ArrayAlias{"foo", "bar"}) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	var sliceTarget EmbeddedSlice
+	if err := Assign(&sliceTarget, sliceSource); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(sliceTarget, sliceSource) {
+		t.Fatalf("expected %#v, got %#v", sliceSource, sliceTarget)
+	}
+
+	var arrayTarget EmbeddedArray
+	if err := Assign(&arrayTarget, arraySource); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if arrayTarget != arraySource {
+		t.Fatalf("expected %#v, got %#v", arraySource, arrayTarget)
+	}
+
+	// Untagged aliases are named like any other field.
+	var untagged Untagged
+	input := map[string]any{
+		"sliceAlias": []string{"a"},
+		"arrayAlias": []string{"b", "c"},
+	}
+	if err := Assign(&untagged, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(untagged.SliceAlias, SliceAlias{"a"}) || untagged.ArrayAlias == nil || *untagged.ArrayAlias != (ArrayAlias{"b", "c"}) {
+		t.Fatalf("bad: %#v", untagged)
+	}
+
+	var kvs []KV
+	if err := Assign(&kvs, sliceSource, func(c *AssignConfig) {
+		c.SortKeys = true
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(kvs) != 2 || kvs[0].Key != "slice_alias" {
+		t.Fatalf("bad: %#v", kvs)
+	}
+}
+
+func TestAssign_StableErrorOrder(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		A int `json:"a"`
+		B int `json:"b"`
+		C int `json:"c"`
+	}
+
+	input := map[string]any{
+		"x": map[string]any{"a": "bad", "b": "bad", "c": "bad"},
+		"y": map[string]any{"a": "bad", "b": "bad", "c": "bad"},
+		"z": []any{"bad", "bad", "bad", "bad", "bad", "bad", "bad", "bad", "bad", "bad", "bad"},
+	}
+
+	type Target struct {
+		X map[string]int `json:"x"`
+		Y Item           `json:"y"`
+		Z []int          `json:"z"`
+	}
+
+	var first *Error
+	for i := 0; i < 20; i++ {
+		var result Target
+		err := Assign(&result, input)
+
+		derr, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("expected *Error, got %T: %v", err, err)
+		}
+
+		if first == nil {
+			first = derr
+			continue
+		}
+		if !reflect.DeepEqual(derr.Errors, first.Errors) {
+			t.Fatalf("unstable errors:\n%#v\n%#v", derr.Errors, first.Errors)
+		}
+		for j, c := range derr.Collection {
+			if c.Path != first.Collection[j].Path {
+				t.Fatalf("unstable collection order at %d: %s != %s", j, c.Path, first.Collection[j].Path)
+			}
+		}
+	}
+
+	if first.Collection[2].Path != "X[c]" || first.Collection[4].Path != "Z[1]" || first.Collection[13].Path != "Z[10]" {
+		t.Fatalf("bad collection order: %v", first.Collection)
+	}
+}
+
+func TestAssign_SourceKeyNormalizer(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		MaxConns int    `json:"maxConns"`
+		Timeout  int    `json:"timeout"`
+		Name     string `json:"name"`
+	}
+
+	normalize := func(key string) string {
+		key = strings.ReplaceAll(key, "-", "")
+		key = strings.ReplaceAll(key, "_", "")
+		return strings.ToLower(key)
+	}
+
+	for _, key := range []string{"max-conns", "max_conns", "maxConns", "MAX_CONNS"} {
+		var md Metadata
+		var result Target
+		err := Assign(&result, map[string]any{key: 10, "name": "x", "other": 1}, func(c *AssignConfig) {
+			c.SourceKeyNormalizer = normalize
+			c.Metadata = &md
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", key, err)
+		}
+		if result.MaxConns != 10 || result.Name != "x" {
+			t.Fatalf("%s: bad: %#v", key, result)
+		}
+		if !reflect.DeepEqual(md.Unused, []string{"other"}) {
+			t.Fatalf("%s: bad unused: %#v", key, md.Unused)
+		}
+	}
+
+	// Exact matches win over normalized ones
+	var result Target
+	if err := Assign(&result, map[string]any{"max_conns": 1, "maxConns": 2}, func(c *AssignConfig) {
+		c.SourceKeyNormalizer = normalize
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.MaxConns != 2 {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestAssign_NilPointers(t *testing.T) {
+	t.Parallel()
+
+	type Inner struct {
+		A int `json:"a"`
+	}
+
+	type Source struct {
+		Count *int    `json:"count"`
+		Inner *Inner  `json:"inner"`
+		Name  *string `json:"name"`
+	}
+
+	encode := func(policy NilPointerPolicy) map[string]any {
+		var m map[string]any
+		if err := Assign(&m, Source{}, func(c *AssignConfig) {
+			c.NilPointers = policy
+		}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return m
+	}
+
+	typed := encode(NilPointerTyped)
+	if p, ok := typed["count"].(*int); !ok || p != nil {
+		t.Fatalf("expected typed nil, got %#v", typed["count"])
+	}
+
+	untyped := encode(NilPointerUntyped)
+	if v, ok := untyped["count"]; !ok || v != nil {
+		t.Fatalf("expected untyped nil, got %#v", untyped)
+	}
+
+	zero := encode(NilPointerZero)
+	expected := map[string]any{"count": 0, "inner": Inner{}, "name": ""}
+	if !reflect.DeepEqual(zero, expected) {
+		t.Fatalf("bad zero: %#v", zero)
+	}
+
+	omitted := encode(NilPointerOmit)
+	if len(omitted) != 0 {
+		t.Fatalf("expected empty map, got %#v", omitted)
+	}
+}
+
+func TestNewAssigner(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Name  string `object:"display_name"`
+		Count int    `object:"count"`
+	}
+
+	as := NewAssigner(func(c *AssignConfig) {
+		c.TagName = "object"
+		c.SkipKeys = []string{"Count"}
+	})
+
+	var result Target
+	if err := as.Assign(&result, map[string]any{"display_name": "foo", "count": 3}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != (Target{Name: "foo"}) {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	// Per call configurations don't change the assigner
+	var md Metadata
+	var withMeta Target
+	if err := as.Assign(&withMeta, map[string]any{"display_name": "bar"}, func(c *AssignConfig) {
+		c.Metadata = &md
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if withMeta.Name != "bar" || !reflect.DeepEqual(md.Keys, []string{"Name"}) {
+		t.Fatalf("bad: %#v %#v", withMeta, md)
+	}
+	if as.assigner.config.Metadata != nil {
+		t.Fatal("per call configuration leaked into the assigner")
+	}
+}
+
+func TestAssign_IntegerExponents(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Int    int    `json:"int"`
+		Int8   int8   `json:"int8"`
+		Uint   uint   `json:"uint"`
+		Number int64  `json:"number"`
+		Strict uint32 `json:"strict"`
+	}
+
+	input := map[string]any{
+		"int":    "1e3",
+		"int8":   "-1.28E2",
+		"uint":   "2.5e1",
+		"number": json.Number("1.5e10"),
+		"strict": "4e0",
+	}
+
+	exponents := func(c *AssignConfig) {
+		c.WeaklyTypedInput = true
+		c.IntegerExponents = true
+	}
+
+	if err := Assign(&Target{}, input, func(c *AssignConfig) { c.WeaklyTypedInput = true }); err == nil {
+		t.Fatal("expected error without IntegerExponents")
+	}
+
+	var result Target
+	if err := Assign(&result, input, exponents); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := Target{Int: 1000, Int8: -128, Uint: 25, Number: 15000000000, Strict: 4}
+	if result != expected {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	for _, bad := range []map[string]any{
+		{"int": "1.5e0"},
+		{"int8": "1.28e2"},
+		{"uint": "-1e1"},
+		{"number": json.Number("1e100")},
+	} {
+		err := Assign(&Target{}, bad, exponents)
+		if err == nil {
+			t.Fatalf("expected error for %v", bad)
+		}
+	}
+
+	err := Assign(&Target{}, map[string]any{"int": "1.5e0"}, exponents)
+	if !strings.Contains(err.Error(), "'1.5e0' is not an integral value") {
+		t.Fatalf("bad error: %s", err)
+	}
+
+	// Huge exponents are rejected before the integer is built
+	err = Assign(&Target{}, map[string]any{"int": "1e600000000", "uint": "-1e600000000"}, exponents)
+	if err == nil || !strings.Contains(err.Error(), "overflows int64") || !strings.Contains(err.Error(), "overflows uint64") {
+		t.Fatalf("bad error: %v", err)
+	}
+}
+
+func TestAssign_SquashMode(t *testing.T) {
+	t.Parallel()
+
+	type Base struct {
+		ID int `json:"id"`
+	}
+
+	type Meta struct {
+		Owner string `json:"owner"`
+	}
+
+	type Target struct {
+		Base
+		*Meta `json:"meta,nosquash"`
+		Name  string `json:"name"`
+	}
+
+	input := Target{Base: Base{ID: 1}, Meta: &Meta{Owner: "ops"}, Name: "x"}
+
+	var flat map[string]any
+	if err := Assign(&flat, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if flat["id"] != 1 || flat["name"] != "x" || flat["meta"] == nil {
+		t.Fatalf("bad default: %#v", flat)
+	}
+
+	var nested map[string]any
+	if err := Assign(&nested, input, func(c *AssignConfig) {
+		c.Squash = SquashTagged
+		c.DeepInterfaceMaps = true
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]any{
+		"base": map[string]any{"id": 1},
+		"meta": map[string]any{"owner": "ops"},
+		"name": "x",
+	}
+	if !reflect.DeepEqual(nested, expected) {
+		t.Fatalf("bad nested: %#v", nested)
+	}
+
+	// Decoding follows the same rules
+	var result Target
+	if err := Assign(&result, expected, func(c *AssignConfig) {
+		c.Squash = SquashTagged
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.ID != 1 || result.Meta == nil || result.Owner != "ops" || result.Name != "x" {
+		t.Fatalf("bad result: %#v", result)
+	}
+}
+
+func TestAssign_MatchName(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		UserName string `json:"userName"`
+		Age      int    `json:"age"`
+		City     string `json:"city"`
+	}
+
+	input := map[string]any{
+		"USERNAME": "ada",
+		"Age":      36,
+		"city":     "London",
+		"CITY":     "Paris",
+	}
+
+	var md Metadata
+	var result Target
+	if err := Assign(&result, input, func(c *AssignConfig) {
+		c.MatchName = strings.EqualFold
+		c.Metadata = &md
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Exact matches win
+	expected := Target{UserName: "ada", Age: 36, City: "London"}
+	if result != expected {
+		t.Fatalf("bad: %#v", result)
+	}
+	if !reflect.DeepEqual(md.Unused, []string{"CITY"}) {
+		t.Fatalf("bad unused: %#v", md.Unused)
+	}
+}
+
+func TestAssign_TypedErrors(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Name  string
+		Count uint
+		Ch    chan int
+	}
+
+	input := map[string]any{
+		"name":  []int{1},
+		"count": -1,
+		"ch":    1,
+		"extra": true,
+	}
+
+	var target Target
+	err := Assign(&target, input, func(c *AssignConfig) {
+		c.ErrorUnused = true
+	})
+
+	expected := "4 error(s) decoding:\n\n" +
+		"* '' has invalid keys: extra\n" +
+		"* 'Name' expected type 'string', got unconvertible type '[]int', value: '[1]'\n" +
+		"* Ch: unsupported type: chan\n" +
+		"* cannot parse 'Count', -1 overflows uint"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error:\n%s\ngot:\n%v", expected, err)
+	}
+
+	var unconvertible *UnconvertibleTypeError
+	if !errors.As(err, &unconvertible) {
+		t.Fatalf("expected an UnconvertibleTypeError")
+	}
+	if unconvertible.TargetType != reflect.TypeOf("") || unconvertible.SourceType != reflect.TypeOf([]int{}) {
+		t.Fatalf("unexpected types: %s to %s", unconvertible.SourceType, unconvertible.TargetType)
+	}
+
+	var unsupported *UnsupportedTypeError
+	if !errors.As(err, &unsupported) || unsupported.Path != "Ch" {
+		t.Fatalf("expected an UnsupportedTypeError at 'Ch', got %v", unsupported)
+	}
+
+	var unused *UnusedKeyError
+	if !errors.As(err, &unused) || !reflect.DeepEqual(unused.Keys, []string{"extra"}) {
+		t.Fatalf("expected an UnusedKeyError for 'extra', got %v", unused)
+	}
+
+	var overflow *OverflowError
+	if !errors.As(err, &overflow) || overflow.Path != "Count" {
+		t.Fatalf("expected an OverflowError at 'Count', got %v", overflow)
+	}
+
+	err = Assign(&target, map[string]any{"count": -1.5})
+	if !errors.As(err, &overflow) {
+		t.Fatalf("expected an OverflowError, got %v", err)
+	}
+	if msg := overflow.Error(); msg != "cannot parse 'Count', -1.500000 overflows uint" {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+}
+
+func TestAssign_FieldErrorSegments(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		Count int
+	}
+	type Order struct {
+		Items []Item
+		Meta  map[string]Item
+	}
+
+	input := map[string]any{
+		"items": []any{map[string]any{"count": 1}, map[string]any{"count": "x"}},
+		"meta":  map[string]any{"a.b": map[string]any{"count": []int{}}},
+	}
+
+	var order Order
+	err := Assign(&order, input)
+
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("expected an Error, got %v", err)
+	}
+
+	fields := e.FieldErrors()
+	if len(fields) != 2 {
+		t.Fatalf("expected 2 field errors, got %v", fields)
+	}
+
+	expected := [][]PathSegment{
+		{{Kind: PathField, Name: "Items"}, {Kind: PathIndex, Name: "1", Index: 1}, {Kind: PathField, Name: "Count"}},
+		{{Kind: PathField, Name: "Meta"}, {Kind: PathMapKey, Name: "a.b"}, {Kind: PathField, Name: "Count"}},
+	}
+	for i, field := range fields {
+		if !reflect.DeepEqual(field.Segments(), expected[i]) {
+			t.Fatalf("expected segments %+v, got %+v", expected[i], field.Segments())
+		}
+	}
+
+	if _, err := SplitPath("a[1"); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestAssign_FailFast(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		A int
+		B int
+		C []int
+	}
+
+	input := map[string]any{
+		"a": "x",
+		"b": "y",
+		"c": []any{1, "z", "w"},
+	}
+
+	var target Target
+	err := Assign(&target, input)
+	var e *Error
+	if !errors.As(err, &e) || len(e.Errors) != 4 {
+		t.Fatalf("expected 4 errors, got %v", err)
+	}
+
+	err = Assign(&target, input, func(c *AssignConfig) {
+		c.FailFast = true
+	})
+	if !errors.As(err, &e) || len(e.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %v", err)
+	}
+
+	// Nested collections stop at their first error too.
+	var slice [][]int
+	err = Assign(&slice, []any{[]any{"a", "b"}, []any{"c"}}, func(c *AssignConfig) {
+		c.FailFast = true
+	})
+	if !errors.As(err, &e) || len(e.Errors) != 1 || !strings.HasPrefix(e.Errors[0], "'[0][0]'") {
+		t.Fatalf("expected a single error at '[0][0]', got %v", err)
+	}
+}
+
+func TestAssign_UnsetReasons(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Name    string
+		Count   int
+		Skipped string
+		Dropped string
+		hidden  string
+	}
+
+	var md Metadata
+	input := map[string]any{
+		"name":    "a",
+		"skipped": "b",
+		"dropped": "drop",
+		"hidden":  "c",
+	}
+	var target Target
+	err := Assign(&target, input, func(c *AssignConfig) {
+		c.Metadata = &md
+		c.SkipKeys = []string{"Skipped"}
+		c.Hook = func(from, to reflect.Type, data any) (any, error) {
+			if data == "drop" {
+				return nil, nil
+			}
+			return data, nil
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]UnsetReason{
+		"Count":   UnsetMissing,
+		"Skipped": UnsetSkipped,
+		"Dropped": UnsetHookNil,
+	}
+	for key, reason := range expected {
+		if md.UnsetReasons[key] != reason {
+			t.Fatalf("expected %s to be unset as %s, got %s (%v)", key, reason, md.UnsetReasons[key], md.UnsetReasons)
+		}
+	}
+	if len(md.UnsetReasons) != len(md.Unset) {
+		t.Fatalf("expected a reason for every unset key, got %v for %v", md.UnsetReasons, md.Unset)
+	}
+	if target.hidden != "" {
+		t.Fatalf("unexported fields must not be set")
+	}
+}
+
+func TestAssign_ReplaceInterfaces(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]any{
+		"vdata": map[string]any{
+			"vstring": "foo",
+		},
+	}
+
+	var result, inner Basic
+	inner.Vint = 42
+	result.Vdata = &inner
+	err := Assign(&result, input, func(c *AssignConfig) {
+		c.ReplaceInterfaces = true
+	})
+	if err != nil {
+		t.Fatalf("got an err: %s", err)
+	}
+
+	expected := map[string]any{"vstring": "foo"}
+	if !reflect.DeepEqual(result.Vdata, expected) {
+		t.Fatalf("bad: %#v", result.Vdata)
+	}
+	if inner.Vstring != "" {
+		t.Fatalf("the replaced value must not be modified: %#v", inner)
+	}
+
+	// Primitives replace typed values too.
+	var iface any = &Basic{}
+	if err := Assign(&iface, 7, func(c *AssignConfig) { c.ReplaceInterfaces = true }); err != nil {
+		t.Fatalf("got an err: %s", err)
+	}
+	if iface != 7 {
+		t.Fatalf("bad: %#v", iface)
+	}
+}
+
+func TestAssign_EmbeddedPointerCycle(t *testing.T) {
+	t.Parallel()
+
+	type Node struct {
+		*Node
+		Name string
+	}
+
+	source := &Node{Name: "root"}
+	source.Node = source
+
+	var result map[string]any
+	if err := Assign(&result, source); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result["name"] != "root" {
+		t.Fatalf("expected 'root', got %#v", result["name"])
+	}
+
+	var target Node
+	if err := Assign(&target, map[string]any{"name": "child"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if target.Name != "child" || target.Node != nil {
+		t.Fatalf("unexpected result %+v", target)
+	}
+
+	var copied Node
+	if err := Assign(&copied, source); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if copied.Name != "root" {
+		t.Fatalf("expected 'root', got '%s'", copied.Name)
+	}
+}
+
+type pointerNamer struct {
+	Name string
+}
+
+func (p *pointerNamer) GetName() string { return p.Name }
+
+func TestAssign_PointerReceiverInterface(t *testing.T) {
+	t.Parallel()
+
+	type namer interface {
+		GetName() string
+	}
+
+	var result struct {
+		Namer namer
+	}
+	err := Assign(&result, map[string]any{"namer": "n"}, func(c *AssignConfig) {
+		c.Hook = func(from reflect.Type, to reflect.Type, data any) (any, error) {
+			if to.Kind() == reflect.Interface && from.Kind() == reflect.String {
+				return pointerNamer{Name: data.(string)}, nil
+			}
+			return data, nil
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Namer == nil || result.Namer.GetName() != "n" {
+		t.Fatalf("unexpected result %#v", result.Namer)
+	}
+
+	// The value held by the interface is replaced, not merged into
+	err = Assign(&result, map[string]any{"namer": pointerNamer{Name: "m"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Namer.GetName() != "m" {
+		t.Fatalf("expected 'm', got '%s'", result.Namer.GetName())
+	}
+
+	var value struct {
+		Namer pointerNamer
+	}
+	if err := Assign(&value, map[string]any{"namer": &pointerNamer{Name: "v"}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value.Namer.Name != "v" {
+		t.Fatalf("expected 'v', got '%s'", value.Namer.Name)
+	}
+}
+
+func TestAssign_Initialisms(t *testing.T) {
+	t.Parallel()
+
+	names := map[string]string{
+		"UserID":     "userID",
+		"ID":         "id",
+		"URLPath":    "urlPath",
+		"HTTPServer": "httpServer",
+		"user_id":    "userID",
+		"APIKey2":    "apiKey2",
+		"V2API":      "v2API",
+		"Name":       "name",
+	}
+	for name, expected := range names {
+		if actual := toLowerCamelInitialisms(name, DefaultInitialisms); actual != expected {
+			t.Fatalf("%s: expected '%s', got '%s'", name, expected, actual)
+		}
+	}
+
+	type User struct {
+		UserID  int
+		HomeURL string
+	}
+
+	var result map[string]any
+	err := Assign(&result, User{UserID: 1, HomeURL: "u"}, func(c *AssignConfig) {
+		c.Initialisms = DefaultInitialisms
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(result, map[string]any{"userID": 1, "homeURL": "u"}) {
+		t.Fatalf("unexpected result %v", result)
+	}
+
+	// Without initialisms the default keys are unchanged
+	result = nil
+	if err := Assign(&result, User{UserID: 1}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := result["userId"]; !ok {
+		t.Fatalf("unexpected result %v", result)
+	}
+}
+
+func TestAssign_SortMetadata(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		Name string `json:"name"`
+	}
+	type Target struct {
+		Items []Item `json:"items"`
+		B     string `json:"b"`
+		A     string `json:"a"`
+		C     string `json:"c"`
+	}
+
+	items := make([]any, 12)
+	for i := range items {
+		items[i] = map[string]any{"name": strconv.Itoa(i)}
+	}
+
+	md := Metadata{Keys: []string{"earlier"}}
+	var result Target
+	err := Assign(&result, map[string]any{"items": items, "b": "b", "a": "a", "z": 1, "y": 2}, func(c *AssignConfig) {
+		c.Metadata = &md
+		c.SortMetadata = true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if md.Keys[0] != "earlier" || md.Keys[1] != "A" || md.Keys[len(md.Keys)-1] != "Items[11].Name" {
+		t.Fatalf("unexpected keys %v", md.Keys)
+	}
+	for i := 2; i < len(md.Keys); i++ {
+		if !pathLess(md.Keys[i-1], md.Keys[i]) {
+			t.Fatalf("keys not sorted: %v", md.Keys)
+		}
+	}
+	if !reflect.DeepEqual(md.Unused, []string{"y", "z"}) || !reflect.DeepEqual(md.Unset, []string{"C"}) {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+}
+
+func TestAssign_AltKeys(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Name  string `json:"name,alt=old_name|legacy_name"`
+		Port  int    `json:"port,omitempty,alt=listen"`
+		Other string `json:"other"`
+	}
+
+	field, _ := reflect.TypeOf(Config{}).FieldByName("Port")
+	if name, opts := ParseTag(field); name != "port" || opts != (TagOptions{OmitEmpty: true, Alt: "listen"}) {
+		t.Fatalf("unexpected tag %q %#v", name, opts)
+	}
+
+	var md Metadata
+	var result Config
+	err := Assign(&result, map[string]any{
+		"legacy_name": "legacy",
+		"old_name":    "old",
+		"listen":      80,
+		"other":       "o",
+	}, func(c *AssignConfig) {
+		c.Metadata = &md
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Config{Name: "old", Port: 80, Other: "o"}
+	if result != expected {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+	if !reflect.DeepEqual(md.AltKeys, map[string]string{"Name": "old_name", "Port": "listen"}) {
+		t.Fatalf("unexpected alt keys %v", md.AltKeys)
+	}
+	if !reflect.DeepEqual(md.Unused, []string{"legacy_name"}) {
+		t.Fatalf("unexpected unused keys %v", md.Unused)
+	}
+
+	// The key of the field takes precedence
+	md = Metadata{}
+	result = Config{}
+	if err := Assign(&result, map[string]any{"name": "new", "old_name": "old"}, func(c *AssignConfig) {
+		c.Metadata = &md
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Name != "new" || len(md.AltKeys) != 0 {
+		t.Fatalf("unexpected result %+v, alt keys %v", result, md.AltKeys)
+	}
+}
+
+func TestError_IsAs(t *testing.T) {
+	t.Parallel()
+
+	sentinel := errors.New("sentinel")
+	e := &Error{
+		Errors: []string{"'Name' sentinel", "Ch: unsupported type: chan"},
+		Causes: []error{
+			NewFieldError("Name", sentinel),
+			&UnsupportedTypeError{Path: "Ch", Type: reflect.TypeOf(make(chan int))},
+		},
+	}
+
+	// The methods match the causes without relying on Unwrap() []error.
+	if !e.Is(sentinel) || e.Is(errors.New("other")) {
+		t.Fatal("expected Is to match the sentinel cause only")
+	}
+
+	var unsupported *UnsupportedTypeError
+	if !e.As(&unsupported) || unsupported.Path != "Ch" {
+		t.Fatalf("expected an UnsupportedTypeError at 'Ch', got %v", unsupported)
+	}
+
+	var overflow *OverflowError
+	if e.As(&overflow) {
+		t.Fatal("expected no OverflowError")
+	}
+}
+
 func testSliceInput(t *testing.T, input map[string]any, expected *Slice) {
 	var result Slice
 	err := Assign(&result, input)
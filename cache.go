@@ -0,0 +1,198 @@
+package object
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Cache is a content addressed cache of assignment results, keyed by a hash
+// of the source and the type of the target. When AssignConfig.Cache is set,
+// assigning a source identical to an earlier one into a zero target of the
+// same type deep copies the cached result instead of decoding it again,
+// which speeds up hot reload loops of unchanged configuration payloads.
+//
+// Results only depend on the source, so a Cache must only be shared by
+// calls using the same configuration. Assignments with Metadata tracking
+// or into non-zero targets bypass the cache. Sources must not be cyclic.
+// A Cache is safe for concurrent use.
+type Cache struct {
+	mu      sync.RWMutex
+	size    int
+	entries map[cacheKey]cacheEntry
+}
+
+// cacheEntry is a cached result with the number of keys assigned to
+// produce it, reported to Metrics on cache hits.
+type cacheEntry struct {
+	val  reflect.Value
+	keys int
+}
+
+type cacheKey struct {
+	hash       [16]byte
+	targetType reflect.Type
+}
+
+// NewCache returns a cache holding at most size results. Once full, an
+// arbitrary entry is evicted for each new result. A size <= 0 means no
+// limit.
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:    size,
+		entries: make(map[cacheKey]cacheEntry),
+	}
+}
+
+// Len returns the number of cached results.
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// Reset removes all cached results.
+func (c *Cache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[cacheKey]cacheEntry)
+}
+
+func (c *Cache) load(key cacheKey) (cacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	val, ok := c.entries[key]
+	return val, ok
+}
+
+func (c *Cache) store(key cacheKey, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exist := c.entries[key]; !exist && c.size > 0 && len(c.entries) >= c.size {
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[key] = entry
+}
+
+// assignCached assigns through the configured cache. It reports whether
+// the assignment was cacheable; if not the caller assigns as usual.
+func (a *assigner) assignCached(targetVal reflect.Value, sourceVal reflect.Value) (bool, error) {
+	if a.config.Metadata != nil || !isZeroValue(targetVal) {
+		return false, nil
+	}
+
+	h := newHash()
+	hashValue(h, sourceVal)
+	key := cacheKey{targetType: targetVal.Type()}
+	copy(key.hash[:], h.Sum(nil))
+
+	if cached, ok := a.config.Cache.load(key); ok {
+		targetVal.Set(deepCopy(cached.val))
+		if a.keys != nil {
+			*a.keys += cached.keys
+		}
+		return true, nil
+	}
+
+	keys := 0
+	if a.keys != nil {
+		keys = *a.keys
+	}
+	if err := a.assign(targetVal, "", sourceVal, ""); err != nil {
+		return true, err
+	}
+	if a.keys != nil {
+		keys = *a.keys - keys
+	}
+
+	a.config.Cache.store(key, cacheEntry{val: deepCopy(targetVal), keys: keys})
+	return true, nil
+}
+
+func newHash() hash.Hash {
+	return fnv.New128a()
+}
+
+// hashValue writes the type and content of val to h. Map entries are
+// hashed in key order so equal maps hash equally.
+func hashValue(h hash.Hash, val reflect.Value) {
+	var buf [8]byte
+	writeUint := func(u uint64) {
+		binary.LittleEndian.PutUint64(buf[:], u)
+		h.Write(buf[:])
+	}
+	writeString := func(s string) {
+		writeUint(uint64(len(s)))
+		h.Write([]byte(s))
+	}
+
+	if !val.IsValid() {
+		writeString("")
+		return
+	}
+	writeString(val.Type().String())
+
+	switch val.Kind() {
+	case reflect.Bool:
+		if val.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(val.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(val.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint(math.Float64bits(val.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		writeUint(math.Float64bits(real(val.Complex())))
+		writeUint(math.Float64bits(imag(val.Complex())))
+	case reflect.String:
+		writeString(val.String())
+	case reflect.Interface, reflect.Ptr:
+		if val.IsNil() {
+			writeUint(0)
+			return
+		}
+		writeUint(1)
+		hashValue(h, val.Elem())
+	case reflect.Slice, reflect.Array:
+		if val.Kind() == reflect.Slice && val.IsNil() {
+			writeUint(0)
+			return
+		}
+		writeUint(uint64(val.Len()) + 1)
+		for i := 0; i < val.Len(); i++ {
+			hashValue(h, val.Index(i))
+		}
+	case reflect.Map:
+		if val.IsNil() {
+			writeUint(0)
+			return
+		}
+		keys := val.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return mapKeyString(keys[i]) < mapKeyString(keys[j])
+		})
+		writeUint(uint64(len(keys)) + 1)
+		for _, k := range keys {
+			hashValue(h, k)
+			hashValue(h, val.MapIndex(k))
+		}
+	case reflect.Struct:
+		for i := 0; i < val.NumField(); i++ {
+			hashValue(h, val.Field(i))
+		}
+	default:
+		// Channels, functions and unsafe pointers are hashed by identity
+		writeUint(uint64(val.Pointer()))
+	}
+}
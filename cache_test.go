@@ -0,0 +1,142 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssign_Cache(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Name  string
+		Ports []int
+		Extra map[string]any
+	}
+
+	cache := NewCache(0)
+	withCache := func(c *AssignConfig) {
+		c.Cache = cache
+	}
+
+	input := map[string]any{
+		"name":  "svc",
+		"ports": []any{80, 443},
+		"extra": map[string]any{"debug": true},
+	}
+
+	var first Config
+	if err := Assign(&first, input, withCache); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("expected one cached result, got %d", cache.Len())
+	}
+
+	// An equal but distinct source hits the cache.
+	same := map[string]any{
+		"extra": map[string]any{"debug": true},
+		"ports": []any{80, 443},
+		"name":  "svc",
+	}
+
+	var second Config
+	if err := Assign(&second, same, withCache); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("expected a cache hit, got %d entries", cache.Len())
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("expected %#v, got %#v", first, second)
+	}
+
+	// Results are deep copies.
+	second.Ports[0] = 8080
+	second.Extra["debug"] = false
+
+	var third Config
+	if err := Assign(&third, input, withCache); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if third.Ports[0] != 80 || third.Extra["debug"] != true {
+		t.Fatalf("cached result was mutated: %#v", third)
+	}
+
+	// Different sources and target types are cached separately.
+	input["name"] = "other"
+	var fourth Config
+	if err := Assign(&fourth, input, withCache); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fourth.Name != "other" {
+		t.Fatalf("bad: %#v", fourth)
+	}
+
+	var asMap map[string]any
+	if err := Assign(&asMap, input, withCache); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cache.Len() != 3 {
+		t.Fatalf("expected three cached results, got %d", cache.Len())
+	}
+
+	// Non zero targets bypass the cache.
+	existing := Config{Name: "keep"}
+	if err := Assign(&existing, map[string]any{"ports": []int{1}}, withCache); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if existing.Name != "keep" || cache.Len() != 3 {
+		t.Fatalf("bad: %#v, %d entries", existing, cache.Len())
+	}
+
+	cache.Reset()
+	if cache.Len() != 0 {
+		t.Fatalf("expected an empty cache")
+	}
+}
+
+func TestCache_Size(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(2)
+	for i := 0; i < 5; i++ {
+		var result int
+		if err := Assign(&result, i, func(c *AssignConfig) {
+			c.Cache = cache
+		}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if result != i {
+			t.Fatalf("expected %d, got %d", i, result)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("expected two cached results, got %d", cache.Len())
+	}
+}
+
+func TestHashValue(t *testing.T) {
+	t.Parallel()
+
+	hash := func(v any) [16]byte {
+		var key [16]byte
+		h := newHash()
+		hashValue(h, reflect.ValueOf(v))
+		copy(key[:], h.Sum(nil))
+		return key
+	}
+
+	if hash(map[string]any{"a": 1, "b": 2}) != hash(map[string]any{"b": 2, "a": 1}) {
+		t.Fatalf("equal maps must hash equally")
+	}
+	if hash(1) == hash(int64(1)) {
+		t.Fatalf("values of different types must hash differently")
+	}
+	if hash([]any{"ab", "c"}) == hash([]any{"a", "bc"}) {
+		t.Fatalf("different slices must hash differently")
+	}
+	if hash([]int(nil)) == hash([]int{}) {
+		t.Fatalf("nil and empty slices must hash differently")
+	}
+}
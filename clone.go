@@ -0,0 +1,105 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Clone returns a deep copy of v: maps, slices, arrays, pointers and the
+// exported fields of structs are copied recursively, so the result shares
+// no mutable state with v. Unexported struct fields are copied shallowly.
+// v must not be cyclic.
+func Clone(v any) any {
+	if v == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(v)).Interface()
+}
+
+// Checkpoint is a deep copy of a value taken by Snapshot.
+type Checkpoint struct {
+	val reflect.Value
+}
+
+// Snapshot takes a deep copy of the value v points to, so it can be rolled
+// back with Restore, e.g. when a configuration fails validation after
+// Assign. v must be a non nil pointer.
+func Snapshot(v any) (Checkpoint, error) {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return Checkpoint{}, errors.New("snapshot source must be a non nil pointer")
+	}
+	return Checkpoint{val: deepCopy(val.Elem())}, nil
+}
+
+// Restore sets the value target points to back to the snapshot. target
+// must be a pointer to a value of the snapshot type. The checkpoint can be
+// restored any number of times.
+func (c Checkpoint) Restore(target any) error {
+	if !c.val.IsValid() {
+		return errors.New("restore from an empty checkpoint")
+	}
+
+	targetVal := reflect.ValueOf(target)
+	if targetVal.Kind() != reflect.Ptr || targetVal.IsNil() {
+		return errors.New("target must be a non nil pointer")
+	}
+
+	targetVal = targetVal.Elem()
+	if targetVal.Type() != c.val.Type() {
+		return fmt.Errorf("cannot restore a snapshot of type '%s' into '%s'", c.val.Type(), targetVal.Type())
+	}
+
+	targetVal.Set(deepCopy(c.val))
+	return nil
+}
+
+// deepCopy returns a copy of val that shares no maps, slices or pointers
+// with it. Unexported struct fields are copied shallowly.
+func deepCopy(val reflect.Value) reflect.Value {
+	copied := reflect.New(val.Type()).Elem()
+
+	switch val.Kind() {
+	case reflect.Interface:
+		if !val.IsNil() {
+			copied.Set(deepCopy(val.Elem()))
+		}
+	case reflect.Ptr:
+		if !val.IsNil() {
+			elem := reflect.New(val.Type().Elem())
+			elem.Elem().Set(deepCopy(val.Elem()))
+			copied.Set(elem)
+		}
+	case reflect.Slice:
+		if !val.IsNil() {
+			copied.Set(reflect.MakeSlice(val.Type(), val.Len(), val.Len()))
+			for i := 0; i < val.Len(); i++ {
+				copied.Index(i).Set(deepCopy(val.Index(i)))
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			copied.Index(i).Set(deepCopy(val.Index(i)))
+		}
+	case reflect.Map:
+		if !val.IsNil() {
+			copied.Set(reflect.MakeMapWithSize(val.Type(), val.Len()))
+			iter := val.MapRange()
+			for iter.Next() {
+				copied.SetMapIndex(deepCopy(iter.Key()), deepCopy(iter.Value()))
+			}
+		}
+	case reflect.Struct:
+		copied.Set(val)
+		for i := 0; i < val.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				field.Set(deepCopy(val.Field(i)))
+			}
+		}
+	default:
+		copied.Set(val)
+	}
+
+	return copied
+}
@@ -0,0 +1,90 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	type Inner struct {
+		Tags []string
+	}
+	type Outer struct {
+		Name   string
+		Inner  *Inner
+		Values map[string]any
+		hidden int
+	}
+
+	source := Outer{
+		Name:   "a",
+		Inner:  &Inner{Tags: []string{"x"}},
+		Values: map[string]any{"list": []any{1, 2}},
+		hidden: 3,
+	}
+
+	cloned := Clone(source).(Outer)
+	if !reflect.DeepEqual(source, cloned) {
+		t.Fatalf("expected %#v, got %#v", source, cloned)
+	}
+
+	cloned.Inner.Tags[0] = "y"
+	cloned.Values["list"].([]any)[0] = 9
+	if source.Inner.Tags[0] != "x" || source.Values["list"].([]any)[0] != 1 {
+		t.Fatalf("clone shares state with the source: %#v", source)
+	}
+
+	if Clone(nil) != nil {
+		t.Fatalf("expected nil")
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Name  string
+		Ports []int
+	}
+
+	config := Config{Name: "svc", Ports: []int{80}}
+	checkpoint, err := Snapshot(&config)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := Assign(&config, map[string]any{"name": "bad", "ports": []int{1, 2}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	config.Ports[0] = 3
+
+	if err := checkpoint.Restore(&config); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := Config{Name: "svc", Ports: []int{80}}
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, config)
+	}
+
+	// Restoring twice yields independent copies.
+	config.Ports[0] = 4
+	if err := checkpoint.Restore(&config); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if config.Ports[0] != 80 {
+		t.Fatalf("checkpoint was mutated: %#v", config)
+	}
+
+	var other map[string]any
+	if err := checkpoint.Restore(&other); err == nil {
+		t.Fatalf("expected a type mismatch error")
+	}
+	if _, err := Snapshot(config); err == nil {
+		t.Fatalf("expected an error for a non pointer")
+	}
+	if err := (Checkpoint{}).Restore(&config); err == nil {
+		t.Fatalf("expected an error for an empty checkpoint")
+	}
+}
@@ -0,0 +1,306 @@
+// Command objectgen generates reflection-free AssignFrom and AssignTo
+// methods for struct types annotated with an "//object:generate" comment.
+// The object package detects these methods and prefers them over
+// reflection when Assign is called with the default configuration.
+//
+// Usage, next to the annotated types:
+//
+//	//go:generate go run github.com/epkgs/object/cmd/objectgen
+//
+// Without arguments the file named by $GOFILE is processed, otherwise each
+// file argument is. The methods for a file "name.go" are written to
+// "name_object.go" in the same directory.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/epkgs/object"
+)
+
+// annotation marks the struct types to generate methods for.
+const annotation = "//object:generate"
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("objectgen: ")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: objectgen [file.go ...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	files := flag.Args()
+	if len(files) == 0 {
+		gofile := os.Getenv("GOFILE")
+		if gofile == "" {
+			flag.Usage()
+			os.Exit(2)
+		}
+		files = []string{gofile}
+	}
+
+	for _, file := range files {
+		if err := generateFile(file); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// generateFile writes the methods of the annotated types of file, if any.
+func generateFile(file string) error {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	out, err := generate(file, src)
+	if err != nil || out == nil {
+		return err
+	}
+
+	name := strings.TrimSuffix(file, filepath.Ext(file)) + "_object.go"
+	return os.WriteFile(name, out, 0o644)
+}
+
+// generate returns the formatted source of the methods of the annotated
+// types of src, or nil if there are none.
+func generate(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var structs []structType
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if !annotated(typeSpec.Doc) && !(len(genDecl.Specs) == 1 && annotated(genDecl.Doc)) {
+				continue
+			}
+			st, err := parseStruct(typeSpec)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", fset.Position(typeSpec.Pos()), err)
+			}
+			structs = append(structs, st)
+		}
+	}
+
+	if len(structs) == 0 {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by objectgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", file.Name.Name)
+	fmt.Fprintf(&buf, "import \"github.com/epkgs/object\"\n")
+	for _, st := range structs {
+		st.writeAssignFrom(&buf)
+		st.writeAssignTo(&buf)
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+func annotated(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.TrimSpace(comment.Text) == annotation {
+			return true
+		}
+	}
+	return false
+}
+
+// structType is an annotated struct type.
+type structType struct {
+	name   string
+	fields []structField
+}
+
+// structField is a field of an annotated struct type.
+type structField struct {
+	name      string
+	key       string
+	typ       ast.Expr
+	omitempty bool
+}
+
+func parseStruct(typeSpec *ast.TypeSpec) (structType, error) {
+	st := structType{name: typeSpec.Name.Name}
+
+	if typeSpec.TypeParams != nil {
+		return st, errors.New("generic types are not supported")
+	}
+
+	structExpr, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return st, fmt.Errorf("%s is not a struct type", st.name)
+	}
+
+	for _, field := range structExpr.Fields.List {
+		if len(field.Names) == 0 {
+			return st, fmt.Errorf("%s: embedded fields are not supported", st.name)
+		}
+
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			unquoted, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return st, err
+			}
+			tag = reflect.StructTag(unquoted)
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+
+			key, opts := object.ParseTag(reflect.StructField{Name: name.Name, Tag: tag})
+			if opts.Skip {
+				continue
+			}
+			if opts.Squash || opts.Zero || opts.Unit != "" || opts.Scale != "" || opts.When != "" || opts.MergeKey != "" || opts.Alt != "" || opts.String || opts.OmitZero {
+				return st, fmt.Errorf("%s: the squash, inline, zero, unit, scale, when, mergekey, alt, string and omitzero tag options are not supported", st.name)
+			}
+
+			st.fields = append(st.fields, structField{
+				name:      name.Name,
+				key:       key,
+				typ:       field.Type,
+				omitempty: opts.OmitEmpty,
+			})
+		}
+	}
+
+	return st, nil
+}
+
+// writeAssignFrom writes the AssignFrom method. Values of builtin scalar
+// types are assigned directly, anything else goes through AssignField.
+func (st structType) writeAssignFrom(buf *bytes.Buffer) {
+	fmt.Fprintf(buf, "\n// AssignFrom implements object.AssignerFrom.\n")
+	fmt.Fprintf(buf, "func (t *%s) AssignFrom(source map[string]any) error {\n", st.name)
+	fmt.Fprintf(buf, "var errs []error\n")
+	for _, f := range st.fields {
+		fmt.Fprintf(buf, "if v, ok := source[%q]; ok {\n", f.key)
+		if isScalar(f.typ) {
+			fmt.Fprintf(buf, "if tv, ok := v.(%s); ok {\n", types.ExprString(f.typ))
+			fmt.Fprintf(buf, "t.%s = tv\n", f.name)
+			fmt.Fprintf(buf, "} else ")
+		}
+		fmt.Fprintf(buf, "if err := object.AssignField(&t.%s, %q, %q, v); err != nil {\n", f.name, f.name, f.key)
+		fmt.Fprintf(buf, "errs = append(errs, err)\n")
+		fmt.Fprintf(buf, "}\n")
+		fmt.Fprintf(buf, "}\n")
+	}
+	fmt.Fprintf(buf, "return object.JoinErrors(errs)\n")
+	fmt.Fprintf(buf, "}\n")
+}
+
+// writeAssignTo writes the AssignTo method. Named types are checked for
+// object.Decimal, which is emitted as its text like reflection does.
+func (st structType) writeAssignTo(buf *bytes.Buffer) {
+	fmt.Fprintf(buf, "\n// AssignTo implements object.AssignerTo.\n")
+	fmt.Fprintf(buf, "func (t %s) AssignTo(target map[string]any) error {\n", st.name)
+	for _, f := range st.fields {
+		field := "t." + f.name
+		if f.omitempty {
+			fmt.Fprintf(buf, "if %s {\n", notEmpty(field, f.typ))
+		}
+		switch {
+		case isNamed(f.typ):
+			fmt.Fprintf(buf, "if dec, ok := any(&%s).(object.Decimal); ok {\n", field)
+			fmt.Fprintf(buf, "target[%q] = dec.String()\n", f.key)
+			fmt.Fprintf(buf, "} else {\n")
+			fmt.Fprintf(buf, "target[%q] = %s\n", f.key, field)
+			fmt.Fprintf(buf, "}\n")
+		case isNamedPointer(f.typ):
+			fmt.Fprintf(buf, "if dec, ok := any(%s).(object.Decimal); ok && %s != nil {\n", field, field)
+			fmt.Fprintf(buf, "target[%q] = dec.String()\n", f.key)
+			fmt.Fprintf(buf, "} else {\n")
+			fmt.Fprintf(buf, "target[%q] = %s\n", f.key, field)
+			fmt.Fprintf(buf, "}\n")
+		default:
+			fmt.Fprintf(buf, "target[%q] = %s\n", f.key, field)
+		}
+		if f.omitempty {
+			fmt.Fprintf(buf, "}\n")
+		}
+	}
+	fmt.Fprintf(buf, "return nil\n")
+	fmt.Fprintf(buf, "}\n")
+}
+
+// notEmpty returns the condition under which an omitempty field is kept.
+func notEmpty(field string, typ ast.Expr) string {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		switch {
+		case t.Name == "string":
+			return field + ` != ""`
+		case t.Name == "bool":
+			return field
+		case isScalar(t):
+			return field + " != 0"
+		case t.Name == "any":
+			return field + " != nil"
+		}
+	case *ast.StarExpr, *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+		return field + " != nil"
+	case *ast.ArrayType, *ast.MapType:
+		return "len(" + field + ") != 0"
+	}
+	return "!object.IsEmpty(" + field + ")"
+}
+
+// scalarTypes are the builtin types assigned directly by AssignFrom.
+var scalarTypes = map[string]bool{
+	"string": true, "bool": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"float32": true, "float64": true, "byte": true, "rune": true,
+}
+
+func isScalar(typ ast.Expr) bool {
+	ident, ok := typ.(*ast.Ident)
+	return ok && scalarTypes[ident.Name]
+}
+
+// isNamed reports whether typ names a type other than a builtin one.
+func isNamed(typ ast.Expr) bool {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return !scalarTypes[t.Name] && t.Name != "any" && t.Name != "error"
+	case *ast.SelectorExpr:
+		return true
+	}
+	return false
+}
+
+func isNamedPointer(typ ast.Expr) bool {
+	star, ok := typ.(*ast.StarExpr)
+	return ok && isNamed(star.X)
+}
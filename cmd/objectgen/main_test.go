@@ -0,0 +1,192 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	src := `package p
+
+//object:generate
+type Person struct {
+	Name   string ` + "`json:\"person_name\"`" + `
+	Age    int    ` + "`json:\",omitempty\"`" + `
+	Emails []string
+	Skip   string ` + "`json:\"-\"`" + `
+	secret string
+}
+
+type Other struct {
+	Value int
+}
+`
+
+	out, err := generate("p.go", []byte(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	code := string(out)
+	expected := []string{
+		"func (t *Person) AssignFrom(source map[string]any) error {",
+		"func (t Person) AssignTo(target map[string]any) error {",
+		`source["person_name"]`,
+		"if tv, ok := v.(int); ok {",
+		`object.AssignField(&t.Emails, "Emails", "emails", v)`,
+		"if t.Age != 0 {",
+	}
+	for _, s := range expected {
+		if !strings.Contains(code, s) {
+			t.Fatalf("expected generated code to contain %q, got:\n%s", s, code)
+		}
+	}
+
+	unexpected := []string{"Other", "Skip", "secret"}
+	for _, s := range unexpected {
+		if strings.Contains(code, s) {
+			t.Fatalf("generated code must not contain %q, got:\n%s", s, code)
+		}
+	}
+}
+
+// generatedProgram compares the generated methods of Person with
+// reflection, which SortMetadata selects without changing the results.
+const generatedProgram = `package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/epkgs/object"
+)
+
+type Group struct {
+	People []Person
+}
+
+func main() {
+	reflection := func(c *object.AssignConfig) {
+		c.SortMetadata = true
+	}
+
+	inputs := []map[string]any{
+		{"person_name": "gopher", "age": 13, "emails": []any{"a", "b"}},
+		{"person_name": 1, "age": "x", "emails": "a"},
+	}
+	for _, input := range inputs {
+		var direct, generated, reflected Person
+		directErr := direct.AssignFrom(input)
+		generatedErr := object.Assign(&generated, input)
+		reflectedErr := object.Assign(&reflected, input, reflection)
+		if !reflect.DeepEqual(direct, reflected) || !reflect.DeepEqual(generated, reflected) ||
+			fmt.Sprint(directErr) != fmt.Sprint(reflectedErr) || fmt.Sprint(generatedErr) != fmt.Sprint(reflectedErr) {
+			fmt.Printf("AssignFrom: %#v (%v), Assign: %#v (%v), reflection: %#v (%v)\n",
+				direct, directErr, generated, generatedErr, reflected, reflectedErr)
+			os.Exit(1)
+		}
+
+		direct = Person{Name: "gopher", Emails: []string{"a"}}
+		generatedMap, reflectedMap := map[string]any{}, map[string]any{}
+		directErr = direct.AssignTo(generatedMap)
+		reflectedErr = object.Assign(&reflectedMap, direct, reflection)
+		if !reflect.DeepEqual(generatedMap, reflectedMap) || fmt.Sprint(directErr) != fmt.Sprint(reflectedErr) {
+			fmt.Printf("AssignTo: %#v (%v), reflection: %#v (%v)\n", generatedMap, directErr, reflectedMap, reflectedErr)
+			os.Exit(1)
+		}
+	}
+
+	nested := []any{map[string]any{"people": []any{inputs[1]}}}
+	var generated, reflected []Group
+	generatedErr := object.Assign(&generated, nested)
+	reflectedErr := object.Assign(&reflected, nested, reflection)
+	if fmt.Sprint(generatedErr) != fmt.Sprint(reflectedErr) {
+		fmt.Printf("nested: %v, reflection: %v\n", generatedErr, reflectedErr)
+		os.Exit(1)
+	}
+}
+`
+
+func TestGenerate_MatchesReflection(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("builds a program")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	src := `package main
+
+//object:generate
+type Person struct {
+	Name   string ` + "`json:\"person_name\"`" + `
+	Age    int    ` + "`json:\",omitempty\"`" + `
+	Emails []string
+}
+`
+	out, err := generate("person.go", []byte(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	dir := t.TempDir()
+	mod := "module p\n\ngo 1.18\n\nrequire github.com/epkgs/object v0.0.0\n\nreplace github.com/epkgs/object => " + root + "\n"
+	files := map[string]string{
+		"go.mod":           mod,
+		"person.go":        src,
+		"person_object.go": string(out),
+		"main.go":          generatedProgram,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	cmd := exec.Command(gobin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off", "GOPROXY=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated code differs from reflection: %s\n%s", err, output)
+	}
+}
+
+func TestGenerate_NoAnnotation(t *testing.T) {
+	t.Parallel()
+
+	out, err := generate("p.go", []byte("package p\n\ntype T struct{ A int }\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if out != nil {
+		t.Fatalf("expected no output, got:\n%s", out)
+	}
+}
+
+func TestGenerate_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	sources := []string{
+		"package p\n\n//object:generate\ntype T struct{ Base }\n",
+		"package p\n\n//object:generate\ntype T struct{ A B `json:\",squash\"` }\n",
+		"package p\n\n//object:generate\ntype T int\n",
+	}
+	for _, src := range sources {
+		if _, err := generate("p.go", []byte(src)); err == nil {
+			t.Fatalf("expected an error for:\n%s", src)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package object
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Codec decodes serialized data, such as the content of a configuration
+// file, into generic values (maps, slices and scalars) that can be
+// assigned to a target.
+type Codec func(data []byte) (any, error)
+
+var codecs = struct {
+	sync.RWMutex
+	formats    map[string]Codec
+	extensions map[string]string
+}{
+	formats:    map[string]Codec{},
+	extensions: map[string]string{},
+}
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+func init() {
+	RegisterCodec("json", decodeJSON, ".json")
+}
+
+// RegisterCodec registers codec for format and the given file extensions,
+// e.g. RegisterCodec("yaml", yamlCodec, ".yaml", ".yml"). The package only
+// ships a JSON codec, other formats are plugged in by the application.
+// Registering a format again replaces its codec.
+func RegisterCodec(format string, codec Codec, extensions ...string) {
+	codecs.Lock()
+	defer codecs.Unlock()
+
+	format = strings.ToLower(format)
+	codecs.formats[format] = codec
+	for _, ext := range extensions {
+		codecs.extensions[strings.ToLower(ext)] = format
+	}
+}
+
+// lookupCodec returns the codec of a format name, file extension or file
+// name.
+func lookupCodec(name string) (string, Codec, bool) {
+	codecs.RLock()
+	defer codecs.RUnlock()
+
+	format := strings.ToLower(name)
+	if ext := filepath.Ext(format); ext != "" {
+		format = codecs.extensions[ext]
+	}
+
+	codec, ok := codecs.formats[format]
+	return format, codec, ok
+}
+
+// DecodeAuto reads r, detects whether its content is JSON or YAML and
+// assigns it to target with the codec registered for that format. Content
+// starting with '{' or '[' that is valid JSON is JSON, anything else is
+// considered YAML, which requires a registered "yaml" codec.
+func DecodeAuto(r io.Reader, target any, configs ...func(c *AssignConfig)) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return decodeFormat(detectFormat(data), data, target, configs...)
+}
+
+// DecodeFormat reads r and assigns its content to target with the codec
+// registered for name, which is either a format ("json"), a file extension
+// (".json") or a file name ("config.json").
+func DecodeFormat(r io.Reader, name string, target any, configs ...func(c *AssignConfig)) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return decodeFormat(name, data, target, configs...)
+}
+
+func decodeFormat(name string, data []byte, target any, configs ...func(c *AssignConfig)) error {
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	format, codec, ok := lookupCodec(name)
+	if !ok {
+		if format == "" {
+			format = name
+		}
+		return fmt.Errorf("no codec registered for format '%s'", format)
+	}
+
+	source, err := codec(data)
+	if err != nil {
+		return fmt.Errorf("error decoding %s: %w", format, err)
+	}
+
+	return Assign(target, NormalizeKeys(source), configs...)
+}
+
+// detectFormat sniffs the format of data.
+func detectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM))
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		return "json"
+	}
+	return "yaml"
+}
+
+// decodeJSON is the JSON codec. Numbers are kept as json.Number so
+// integers don't lose precision.
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	// Decode stops after the first value, anything but whitespace after
+	// it is an error
+	if token, err := decoder.Token(); err == nil {
+		return nil, fmt.Errorf("unexpected '%v' after top-level value", token)
+	} else if err != io.EOF {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,154 @@
+package object
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// lineCodec decodes "key: value" lines, standing in for a YAML codec.
+func lineCodec(data []byte) (any, error) {
+	result := map[any]any{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), ":"); ok {
+			result[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return result, scanner.Err()
+}
+
+type codecConfig struct {
+	Name string
+	Port int
+}
+
+func TestDecodeAuto_JSON(t *testing.T) {
+	t.Parallel()
+
+	var result codecConfig
+	input := "\xef\xbb\xbf\n  {\"name\": \"svc\", \"port\": 8080}"
+	if err := DecodeAuto(strings.NewReader(input), &result); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Name != "svc" || result.Port != 8080 {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	var list []int
+	if err := DecodeAuto(strings.NewReader("[1, 2]"), &list); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(list) != 2 || list[1] != 2 {
+		t.Fatalf("bad: %#v", list)
+	}
+}
+
+func TestDecodeAuto_YAML(t *testing.T) {
+	var result codecConfig
+	err := DecodeAuto(strings.NewReader("name: svc\nport: 8080\n"), &result)
+	if err == nil || !strings.Contains(err.Error(), "'yaml'") {
+		t.Fatalf("expected a missing codec error, got %v", err)
+	}
+
+	RegisterCodec("YAML", lineCodec, ".yaml", ".YML")
+	defer func() {
+		codecs.Lock()
+		delete(codecs.formats, "yaml")
+		delete(codecs.extensions, ".yaml")
+		delete(codecs.extensions, ".yml")
+		codecs.Unlock()
+	}()
+
+	err = DecodeAuto(strings.NewReader("name: svc\nport: 8080\n"), &result, func(c *AssignConfig) {
+		c.WeaklyTypedInput = true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Name != "svc" || result.Port != 8080 {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	result = codecConfig{}
+	if err := DecodeFormat(strings.NewReader("name: other"), "config.yml", &result); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Name != "other" {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestDecodeFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"json", "JSON", ".json", "dir/config.json"} {
+		var result codecConfig
+		if err := DecodeFormat(strings.NewReader(`{"name": "svc"}`), name, &result); err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+		if result.Name != "svc" {
+			t.Fatalf("%s: bad: %#v", name, result)
+		}
+	}
+
+	var result codecConfig
+	if err := DecodeFormat(strings.NewReader(`{`), "json", &result); err == nil {
+		t.Fatalf("expected a syntax error")
+	}
+	for _, trailing := range []string{`{"name": "a"} {"name": "b"}`, `{"name": "a"} x`, `{"name": "a"}]`} {
+		if err := DecodeFormat(strings.NewReader(trailing), "json", &result); err == nil {
+			t.Fatalf("expected an error for the data after %q", trailing)
+		}
+	}
+	if err := DecodeFormat(strings.NewReader("{\"name\": \"a\"}\n\t "), "json", &result); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := DecodeFormat(strings.NewReader(`a = 1`), "config.toml", &result); err == nil {
+		t.Fatalf("expected a missing codec error")
+	}
+}
+
+func TestDecodeFormat_RawMessages(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Version  int                        `json:"version"`
+		Sections map[string]json.RawMessage `json:"sections"`
+	}
+
+	input := `{
+		"version": 2,
+		"sections": {
+			"server": {"port": 8080, "hosts": ["a", "b"]},
+			"limit": 12345678901234567890,
+			"name": "main"
+		}
+	}`
+
+	var config Config
+	if err := DecodeFormat(strings.NewReader(input), "json", &config); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]string{
+		"server": `{"hosts":["a","b"],"port":8080}`,
+		"limit":  `12345678901234567890`,
+		"name":   `"main"`,
+	}
+	for key, raw := range expected {
+		if string(config.Sections[key]) != raw {
+			t.Fatalf("bad section %s: %s", key, config.Sections[key])
+		}
+	}
+
+	// Sections can be decoded later
+	var server struct {
+		Port int `json:"port"`
+	}
+	if err := json.Unmarshal(config.Sections["server"], &server); err != nil || server.Port != 8080 {
+		t.Fatalf("bad server: %#v, %v", server, err)
+	}
+}
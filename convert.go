@@ -0,0 +1,100 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Convert assigns source to a new value of type T and returns it, see
+// Assign.
+func Convert[T any](source any, configs ...func(c *AssignConfig)) (T, error) {
+	var target T
+	err := Assign(&target, source, configs...)
+	return target, err
+}
+
+// MustConvert is like Convert but panics if the assignment fails.
+func MustConvert[T any](source any, configs ...func(c *AssignConfig)) T {
+	target, err := Convert[T](source, configs...)
+	if err != nil {
+		panic(err)
+	}
+	return target
+}
+
+// DecodeSlice assigns each row to a new value of type T, see Assign.
+// Errors are reported with the index of their row, e.g. "[2].Name".
+func DecodeSlice[T any](rows []map[string]any, configs ...func(c *AssignConfig)) ([]T, error) {
+	var result []T
+	err := Assign(&result, rows, configs...)
+	return result, err
+}
+
+// ConvertTo assigns value to a new value of type to and returns it, see
+// Assign. It is Convert for types only known at run time.
+func ConvertTo(value any, to reflect.Type, configs ...func(c *AssignConfig)) (any, error) {
+	if to == nil {
+		return nil, errors.New("target type must not be nil")
+	}
+
+	target := reflect.New(to)
+	if err := Assign(target.Interface(), value, configs...); err != nil {
+		return nil, err
+	}
+	return target.Elem().Interface(), nil
+}
+
+// ConvertBetween assigns oldVal, typically a struct of a previous version
+// of a schema, to newPtr after moving its values according to renameMap.
+// renameMap maps paths of oldVal to paths of the new shape using the path
+// syntax of Document, e.g. {"name": "profile.displayName"}, where paths are
+// made of keys as Assign would produce them. All values are read before
+// any is moved, so keys can be swapped. Paths missing in oldVal are
+// ignored, values that aren't renamed keep their key.
+func ConvertBetween(oldVal any, newPtr any, renameMap map[string]string, configs ...func(c *AssignConfig)) error {
+	sourceVal, err := genericSource(oldVal, configs)
+	if err != nil {
+		return err
+	}
+
+	doc := Document{}
+	if sourceVal.IsValid() {
+		if err := doc.Merge(sourceVal.Interface(), configs...); err != nil {
+			return err
+		}
+	}
+
+	oldPaths := make([]string, 0, len(renameMap))
+	for oldPath := range renameMap {
+		oldPaths = append(oldPaths, oldPath)
+	}
+	sort.Slice(oldPaths, func(i, j int) bool {
+		return pathLess(oldPaths[i], oldPaths[j])
+	})
+
+	values := make(map[string]any, len(oldPaths))
+	for _, oldPath := range oldPaths {
+		if value, ok := doc.Get(oldPath); ok {
+			values[oldPath] = value
+		}
+	}
+
+	// Paths are deleted in reverse order so slice indexes stay valid
+	for i := len(oldPaths) - 1; i >= 0; i-- {
+		doc.Delete(oldPaths[i])
+	}
+
+	for _, oldPath := range oldPaths {
+		value, ok := values[oldPath]
+		if !ok {
+			continue
+		}
+		if err := doc.Set(renameMap[oldPath], value); err != nil {
+			return fmt.Errorf("error moving '%s': %w", oldPath, err)
+		}
+	}
+
+	return doc.Decode(newPtr, configs...)
+}
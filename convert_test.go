@@ -0,0 +1,201 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	result, err := Convert[Target](map[string]any{"name": "foo", "count": 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != (Target{Name: "foo", Count: 2}) {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	ptr, err := Convert[*Target](map[string]any{"name": "bar"})
+	if err != nil || ptr == nil || ptr.Name != "bar" {
+		t.Fatalf("bad: %#v, %v", ptr, err)
+	}
+
+	if _, err := Convert[Target](map[string]any{"count": "x"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if n := MustConvert[int]("42", func(c *AssignConfig) {
+		c.WeaklyTypedInput = true
+	}); n != 42 {
+		t.Fatalf("bad: %d", n)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	MustConvert[Target](map[string]any{"count": "x"})
+}
+
+func TestDecodeSlice(t *testing.T) {
+	t.Parallel()
+
+	type Row struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	rows := []map[string]any{
+		{"id": 1, "name": "a"},
+		{"id": 2, "name": "b"},
+	}
+
+	result, err := DecodeSlice[Row](rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []Row{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+
+	rows = append(rows, map[string]any{"id": "x", "name": "c"})
+	result, err = DecodeSlice[Row](rows)
+	e, ok := err.(*Error)
+	if !ok || len(e.Collection) != 1 || e.Collection[0].Index != 2 || e.Collection[0].Path != "[2]" {
+		t.Fatalf("expected an error for row 2, got %v", err)
+	}
+	if len(result) != 3 || result[2].Name != "c" {
+		t.Fatalf("expected the valid fields of every row, got %+v", result)
+	}
+
+	if result, err := DecodeSlice[Row](nil); err != nil || len(result) != 0 {
+		t.Fatalf("expected an empty result, got %+v (%v)", result, err)
+	}
+
+	// Slice merge options have no target elements to merge with
+	result, err = DecodeSlice[Row](rows[:2], MergeWith(MergeOverwrite, SliceMergeAppend))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestConvertTo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    any
+		to       reflect.Type
+		configs  []func(c *AssignConfig)
+		expected any
+	}{
+		{"42", reflect.TypeOf(int64(0)), []func(c *AssignConfig){func(c *AssignConfig) { c.NumberStrings = true }}, int64(42)},
+		{42, reflect.TypeOf(""), []func(c *AssignConfig){func(c *AssignConfig) { c.WeaklyTypedInput = true }}, "42"},
+		{1500, reflect.TypeOf(time.Duration(0)), nil, time.Duration(1500)},
+		{[]any{1, 2}, reflect.TypeOf([]uint8{}), nil, []uint8{1, 2}},
+		{"x", reflect.TypeOf((*string)(nil)), nil, func() *string { s := "x"; return &s }()},
+	}
+
+	for _, test := range tests {
+		result, err := ConvertTo(test.value, test.to, test.configs...)
+		if err != nil {
+			t.Fatalf("%v to %s: unexpected error: %s", test.value, test.to, err)
+		}
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Fatalf("%v to %s: expected %#v, got %#v", test.value, test.to, test.expected, result)
+		}
+	}
+
+	// Hooks apply like they do to fields.
+	upper := func(c *AssignConfig) {
+		c.Hook = func(from, to reflect.Type, data any) (any, error) {
+			if s, ok := data.(string); ok {
+				return s + "!", nil
+			}
+			return data, nil
+		}
+	}
+	if result, err := ConvertTo("hi", reflect.TypeOf(""), upper); err != nil || result != "hi!" {
+		t.Fatalf("expected 'hi!', got %v (%v)", result, err)
+	}
+
+	if _, err := ConvertTo("x", reflect.TypeOf(0)); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if _, err := ConvertTo("x", nil); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestConvertBetween(t *testing.T) {
+	t.Parallel()
+
+	type V1 struct {
+		Name    string `json:"name"`
+		Email   string `json:"email"`
+		Age     int    `json:"age"`
+		First   string `json:"first"`
+		Second  string `json:"second"`
+		Removed string `json:"removed"`
+	}
+
+	type Profile struct {
+		DisplayName string `json:"displayName"`
+		Email       string `json:"email"`
+	}
+
+	type V2 struct {
+		Profile Profile `json:"profile"`
+		Age     int     `json:"age"`
+		First   string  `json:"first"`
+		Second  string  `json:"second"`
+	}
+
+	old := V1{Name: "Ada", Email: "ada@example.com", Age: 36, First: "1", Second: "2", Removed: "x"}
+
+	var result V2
+	err := ConvertBetween(old, &result, map[string]string{
+		"name":    "profile.displayName",
+		"email":   "profile.email",
+		"first":   "second",
+		"second":  "first",
+		"missing": "profile.missing",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := V2{
+		Profile: Profile{DisplayName: "Ada", Email: "ada@example.com"},
+		Age:     36,
+		First:   "2",
+		Second:  "1",
+	}
+	if result != expected {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	// The old value is left untouched
+	source := map[string]any{"nested": map[string]any{"a": 1}}
+	var m map[string]any
+	if err := ConvertBetween(source, &m, map[string]string{"nested.a": "b"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := source["nested"].(map[string]any)["a"]; !ok {
+		t.Fatalf("source modified: %#v", source)
+	}
+	if m["b"] != 1 {
+		t.Fatalf("bad: %#v", m)
+	}
+}
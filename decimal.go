@@ -0,0 +1,89 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Decimal is implemented by arbitrary precision decimal types. Fields whose
+// type (or pointer to type) implements Decimal are populated from strings,
+// json.Number and numeric sources through SetString, and are emitted as
+// their String() text when converting structs to maps or strings. This lets
+// external decimal packages plug in without any extra configuration.
+type Decimal interface {
+	// SetString sets the value from its decimal text representation.
+	SetString(s string) error
+
+	// String returns the decimal text representation of the value.
+	String() string
+}
+
+var decimalType = reflect.TypeOf((*Decimal)(nil)).Elem()
+
+// asDecimal returns the Decimal implementation of val. Non addressable
+// values are copied so that pointer receivers can be used.
+func asDecimal(val reflect.Value) (Decimal, bool) {
+	if !val.IsValid() {
+		return nil, false
+	}
+
+	valType := val.Type()
+	if valType.Implements(decimalType) {
+		if isPtrAble(val.Kind()) && val.IsNil() {
+			return nil, false
+		}
+		return val.Interface().(Decimal), true
+	}
+
+	if val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		return nil, false
+	}
+
+	if !reflect.PointerTo(valType).Implements(decimalType) {
+		return nil, false
+	}
+
+	if !val.CanAddr() {
+		copied := reflect.New(valType)
+		copied.Elem().Set(val)
+		val = copied.Elem()
+	}
+
+	return val.Addr().Interface().(Decimal), true
+}
+
+// decimalTarget returns the Decimal implementation of an addressable,
+// non pointer target.
+func decimalTarget(targetVal reflect.Value) (Decimal, bool) {
+	switch targetVal.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return nil, false
+	}
+
+	if !targetVal.CanAddr() {
+		return nil, false
+	}
+
+	return asDecimal(targetVal)
+}
+
+func (a *assigner) assignDecimal(dec Decimal, targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value) error {
+	sourceVal = reflect.Indirect(sourceVal)
+
+	var str string
+	if src, ok := asDecimal(sourceVal); ok {
+		str = src.String()
+	} else if isString(sourceVal.Kind()) {
+		str = sourceVal.String()
+	} else if s, ok := formatNumber(sourceVal); ok {
+		str = s
+	} else {
+		return newUnconvertibleTypeError(targetKey, targetVal.Type(), sourceVal)
+	}
+
+	if err := dec.SetString(str); err != nil {
+		return fmt.Errorf("cannot parse '%s' as decimal: %s", targetKey.String(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type testDecimal struct {
+	text string
+}
+
+func (d *testDecimal) SetString(s string) error {
+	if !isNumberText(s) {
+		return fmt.Errorf("invalid decimal %q", s)
+	}
+	d.text = s
+	return nil
+}
+
+func (d *testDecimal) String() string {
+	return d.text
+}
+
+func TestAssign_Decimal(t *testing.T) {
+	t.Parallel()
+
+	type Order struct {
+		Price    testDecimal
+		Discount *testDecimal
+		Tax      testDecimal
+	}
+
+	input := map[string]any{
+		"price":    "19.99",
+		"discount": json.Number("0.10"),
+		"tax":      2,
+	}
+
+	var result Order
+	if err := Assign(&result, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if result.Price.text != "19.99" {
+		t.Fatalf("bad price: %#v", result.Price)
+	}
+	if result.Discount == nil || result.Discount.text != "0.10" {
+		t.Fatalf("bad discount: %#v", result.Discount)
+	}
+	if result.Tax.text != "2" {
+		t.Fatalf("bad tax: %#v", result.Tax)
+	}
+
+	actual := map[string]any{}
+	if err := Assign(&actual, result); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]any{
+		"price":    "19.99",
+		"discount": "0.10",
+		"tax":      "2",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, actual)
+	}
+
+	var str string
+	if err := Assign(&str, &result.Price); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if str != "19.99" {
+		t.Fatalf("bad string: %q", str)
+	}
+
+	var invalid Order
+	if err := Assign(&invalid, map[string]any{"price": "abc"}); err == nil {
+		t.Fatal("expected error for invalid decimal")
+	}
+}
@@ -0,0 +1,21 @@
+package object
+
+import "reflect"
+
+// DefaultTagName is the struct tag holding the default value of a field,
+// assigned when AssignConfig.ApplyDefaults is set, e.g.
+// `json:"port" default:"8080"`.
+const DefaultTagName = "default"
+
+// assignDefault assigns the default tag of field, if any, when the source
+// has no value for the field and the field is zero. The default is
+// converted with weak typing, like map keys, and the unit= and scale=
+// options of the field apply.
+func (a *assigner) assignDefault(field fieldInfo, targetKey metaKey) error {
+	value, ok := field.field.Tag.Lookup(DefaultTagName)
+	if !ok || !field.fieldVal.CanSet() || !isZeroValue(field.fieldVal) {
+		return nil
+	}
+
+	return a.keyAssigner.assignField(field, targetKey, reflect.ValueOf(value), "")
+}
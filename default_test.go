@@ -0,0 +1,70 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAssign_ApplyDefaults(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Host    string        `json:"host" default:"localhost"`
+		Port    int           `json:"port" default:"8080"`
+		Debug   bool          `json:"debug" default:"true"`
+		Timeout time.Duration `json:"timeout,unit=ms" default:"500"`
+		Ratio   float64       `json:"ratio" default:"0.5"`
+		Name    string        `json:"name"`
+	}
+
+	defaults := func(c *AssignConfig) {
+		c.ApplyDefaults = true
+	}
+
+	var md Metadata
+	result := Config{Ratio: 2}
+	err := Assign(&result, map[string]any{"port": 9000}, defaults, func(c *AssignConfig) {
+		c.Metadata = &md
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Config{
+		Host:    "localhost",
+		Port:    9000,
+		Debug:   true,
+		Timeout: 500 * time.Millisecond,
+		Ratio:   2,
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+	if md.UnsetReasons["Host"] != UnsetMissing {
+		t.Fatalf("expected Host to be reported unset, got %v", md.UnsetReasons)
+	}
+
+	var fromStruct Config
+	if err := Assign(&fromStruct, struct{ Name string }{Name: "n"}, defaults); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fromStruct.Host != "localhost" || fromStruct.Name != "n" {
+		t.Fatalf("unexpected result %+v", fromStruct)
+	}
+
+	var invalid struct {
+		Port int `json:"port" default:"http"`
+	}
+	if err := Assign(&invalid, map[string]any{}, defaults); err == nil {
+		t.Fatalf("expected an error for an invalid default")
+	}
+
+	var ignored Config
+	if err := Assign(&ignored, map[string]any{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ignored.Host != "" {
+		t.Fatalf("expected defaults to be opt-in, got %+v", ignored)
+	}
+}
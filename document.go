@@ -0,0 +1,195 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Document is a dynamic object backed by a map[string]any. It allows a
+// payload to be inspected and modified by path (e.g. "server.ports[0]")
+// before it is decoded into a struct.
+type Document map[string]any
+
+// NewDocument returns a Document backed by data. A nil data creates an
+// empty document.
+func NewDocument(data map[string]any) Document {
+	if data == nil {
+		data = map[string]any{}
+	}
+	return Document(data)
+}
+
+// Get returns the value at path and whether it exists.
+func (d Document) Get(path string) (any, bool) {
+	segments, err := parsePath(path)
+	if err != nil || len(segments) == 0 {
+		return nil, false
+	}
+
+	val, ok := lookupPath(reflect.ValueOf(map[string]any(d)), segments)
+	if !ok {
+		return nil, false
+	}
+
+	return val.Interface(), true
+}
+
+// Has reports whether a value exists at path.
+func (d Document) Has(path string) bool {
+	_, ok := d.Get(path)
+	return ok
+}
+
+// Set stores value at path, creating the intermediate maps and slices
+// as needed. Slices are grown with nil elements to fit the index. A nil
+// Document can't be set, see NewDocument.
+func (d Document) Set(path string, value any) error {
+	if d == nil {
+		return fmt.Errorf("cannot set '%s' on a nil document", path)
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("invalid path '%s': empty path", path)
+	}
+
+	_, err = setPath(map[string]any(d), segments, value, true)
+	return err
+}
+
+// Delete removes the value at path, reporting whether it existed. Slice
+// elements are removed and the following elements shifted.
+func (d Document) Delete(path string) bool {
+	segments, err := parsePath(path)
+	if err != nil || len(segments) == 0 {
+		return false
+	}
+
+	_, deleted := deletePath(map[string]any(d), segments)
+	return deleted
+}
+
+// Decode assigns the document to target, see Assign.
+func (d Document) Decode(target any, configs ...func(c *AssignConfig)) error {
+	return Assign(target, map[string]any(d), configs...)
+}
+
+// Merge deep merges source (a map or a struct) into the document. Nested
+// maps are merged key by key, other values are replaced, whatever their
+// type. configs apply to the conversion of source to a document, see
+// Assign. A nil Document can't be merged into, see NewDocument.
+func (d Document) Merge(source any, configs ...func(c *AssignConfig)) error {
+	if d == nil {
+		return fmt.Errorf("cannot merge into a nil document")
+	}
+
+	var data map[string]any
+	if err := Assign(&data, source, configs...); err != nil {
+		return err
+	}
+	mergeDocument(map[string]any(d), data)
+	return nil
+}
+
+// mergeDocument merges source into target, recursing into the maps held
+// by both.
+func mergeDocument(target, source map[string]any) {
+	for key, value := range source {
+		if sourceMap, ok := value.(map[string]any); ok {
+			if targetMap, ok := target[key].(map[string]any); ok {
+				mergeDocument(targetMap, sourceMap)
+				continue
+			}
+		}
+		target[key] = value
+	}
+}
+
+// setPath stores value at segments below container and returns the
+// container, which is created or grown when needed. Slices are grown
+// with nil elements when sparse is true. Otherwise only index 0 creates a
+// slice, other indexes creating maps, and an index can be at most the
+// length of its slice.
+func setPath(container any, segments []pathSegment, value any, sparse bool) (any, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	seg := segments[0]
+	rest := segments[1:]
+
+	switch c := container.(type) {
+	case nil:
+		if seg.isIndex && (sparse || seg.index == 0) {
+			return setPath(make([]any, 0), segments, value, sparse)
+		}
+		return setPath(map[string]any{}, segments, value, sparse)
+
+	case map[string]any:
+		child, err := setPath(c[seg.key], rest, value, sparse)
+		if err != nil {
+			return nil, err
+		}
+		c[seg.key] = child
+		return c, nil
+
+	case []any:
+		if !seg.isIndex || seg.index < 0 {
+			return nil, fmt.Errorf("'%s' is not a valid slice index", seg)
+		}
+		if !sparse && seg.index > len(c) {
+			return nil, fmt.Errorf("'%s' skips the indexes from %d", seg, len(c))
+		}
+		for len(c) <= seg.index {
+			c = append(c, nil)
+		}
+		child, err := setPath(c[seg.index], rest, value, sparse)
+		if err != nil {
+			return nil, err
+		}
+		c[seg.index] = child
+		return c, nil
+
+	default:
+		return nil, fmt.Errorf("cannot set '%s' on value of type '%T'", seg, container)
+	}
+}
+
+// deletePath removes the value at segments below container and returns
+// the container, which may be shrunk.
+func deletePath(container any, segments []pathSegment) (any, bool) {
+	seg := segments[0]
+	rest := segments[1:]
+
+	switch c := container.(type) {
+	case map[string]any:
+		child, exist := c[seg.key]
+		if !exist {
+			return c, false
+		}
+		if len(rest) == 0 {
+			delete(c, seg.key)
+			return c, true
+		}
+		child, deleted := deletePath(child, rest)
+		c[seg.key] = child
+		return c, deleted
+
+	case []any:
+		if !seg.isIndex || seg.index < 0 || seg.index >= len(c) {
+			return c, false
+		}
+		if len(rest) == 0 {
+			return append(c[:seg.index], c[seg.index+1:]...), true
+		}
+		child, deleted := deletePath(c[seg.index], rest)
+		c[seg.index] = child
+		return c, deleted
+
+	default:
+		return container, false
+	}
+}
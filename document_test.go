@@ -0,0 +1,127 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	t.Parallel()
+
+	doc := NewDocument(map[string]any{
+		"server": map[string]any{
+			"host":  "localhost",
+			"ports": []any{80, 443},
+		},
+	})
+
+	if v, ok := doc.Get("server.host"); !ok || v != "localhost" {
+		t.Fatalf("bad host: %#v", v)
+	}
+	if v, ok := doc.Get("server.ports[1]"); !ok || v != 443 {
+		t.Fatalf("bad port: %#v", v)
+	}
+	if doc.Has("server.ports[2]") || doc.Has("server.missing") || doc.Has("server.host.deeper") {
+		t.Fatal("unexpected value")
+	}
+
+	if err := doc.Set("server.ports[2]", 8080); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := doc.Set("database.replicas[1].host", "db2"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := doc.Set("server.host.deeper", 1); err == nil {
+		t.Fatal("expected error when setting through a string")
+	}
+
+	if !doc.Delete("server.ports[0]") {
+		t.Fatal("expected port to be deleted")
+	}
+	if doc.Delete("server.missing") {
+		t.Fatal("unexpected delete")
+	}
+
+	expected := Document{
+		"server": map[string]any{
+			"host":  "localhost",
+			"ports": []any{443, 8080},
+		},
+		"database": map[string]any{
+			"replicas": []any{nil, map[string]any{"host": "db2"}},
+		},
+	}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, doc)
+	}
+
+	type Server struct {
+		Host  string
+		Ports []int
+	}
+
+	if err := doc.Merge(map[string]any{"server": map[string]any{"host": "example.com"}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var config struct {
+		Server Server
+	}
+	if err := doc.Decode(&config); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedServer := Server{Host: "example.com", Ports: []int{443, 8080}}
+	if !reflect.DeepEqual(config.Server, expectedServer) {
+		t.Fatalf("expected: %#v, got: %#v", expectedServer, config.Server)
+	}
+}
+
+func TestDocument_MergeReplaces(t *testing.T) {
+	t.Parallel()
+
+	doc := Document{"a": 1, "b": map[string]any{"c": []any{1}, "d": true}}
+	err := doc.Merge(map[string]any{"a": "one", "b": map[string]any{"c": "two"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Document{"a": "one", "b": map[string]any{"c": "two", "d": true}}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, doc)
+	}
+
+	var empty Document
+	if err := empty.Set("a", 1); err == nil {
+		t.Fatal("expected error when setting a nil document")
+	}
+	if err := empty.Merge(map[string]any{"a": 1}); err == nil {
+		t.Fatal("expected error when merging into a nil document")
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	t.Parallel()
+
+	segments, err := parsePath("a.b[2][x.y].c")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []pathSegment{
+		{key: "a"},
+		{key: "b"},
+		{key: "2", index: 2, isIndex: true},
+		{key: "x.y"},
+		{key: "c"},
+	}
+	if !reflect.DeepEqual(segments, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, segments)
+	}
+
+	for _, invalid := range []string{"a..b", "a.", "a[1", "a[]", ".a"} {
+		if _, err := parsePath(invalid); err == nil {
+			t.Fatalf("expected error for %q", invalid)
+		}
+	}
+}
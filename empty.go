@@ -0,0 +1,48 @@
+package object
+
+import "reflect"
+
+// IsZero reports whether v is the zero value of its type. It uses the same
+// optimized check the assigner uses internally and treats nil as zero.
+func IsZero(v any) bool {
+	if v == nil {
+		return true
+	}
+	return isZeroValue(reflect.ValueOf(v))
+}
+
+// IsEmpty reports whether v is empty in the sense of the omitempty tag
+// option: false, 0, nil pointers and interfaces, and empty arrays, slices,
+// maps and strings. Structs are never empty. Nil is empty.
+func IsEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+	return isEmptyValue(reflect.ValueOf(v))
+}
+
+type isZeroer interface {
+	IsZero() bool
+}
+
+var isZeroerType = reflect.TypeOf((*isZeroer)(nil)).Elem()
+
+// isOmitZero reports whether a field tagged with omitzero should be
+// omitted. Like encoding/json, the IsZero method of the value decides
+// when it has one, such as time.Time, nil pointers are zero and other
+// values are compared with the zero value of their type.
+func isOmitZero(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return true
+	}
+	if v.Type().Implements(isZeroerType) {
+		return v.Interface().(isZeroer).IsZero()
+	}
+	if v.CanAddr() && reflect.PointerTo(v.Type()).Implements(isZeroerType) {
+		return v.Addr().Interface().(isZeroer).IsZero()
+	}
+	return isZeroValue(v)
+}
@@ -0,0 +1,65 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testZeroer struct {
+	Value int
+}
+
+func (z testZeroer) IsZero() bool {
+	return z.Value < 0
+}
+
+func TestAssign_OmitZero(t *testing.T) {
+	t.Parallel()
+
+	type Event struct {
+		Name    string     `json:"name,omitzero"`
+		Count   int        `json:"count,omitzero"`
+		At      time.Time  `json:"at,omitzero"`
+		Until   *time.Time `json:"until,omitzero"`
+		Custom  testZeroer `json:"custom,omitzero"`
+		Created time.Time  `json:"created,omitempty"`
+	}
+
+	out := map[string]any{}
+	if err := Assign(&out, Event{Custom: testZeroer{Value: -1}}); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	// omitempty never omits structs, omitzero uses IsZero
+	if _, ok := out["created"]; !ok || len(out) != 1 {
+		t.Fatalf("Assign() = %#v, want only created", out)
+	}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	out = map[string]any{}
+	if err := Assign(&out, Event{Name: "x", At: at, Until: &at}); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	for _, key := range []string{"name", "at", "until", "custom"} {
+		if _, ok := out[key]; !ok {
+			t.Fatalf("Assign() = %#v, missing %s", out, key)
+		}
+	}
+	if _, ok := out["count"]; ok {
+		t.Fatalf("Assign() = %#v, want count omitted", out)
+	}
+
+	// Struct to struct assignments skip zero fields too.
+	target := Event{Count: 3}
+	if err := Assign(&target, Event{Name: "y"}); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if target.Name != "y" || target.Count != 3 {
+		t.Fatalf("Assign() = %+v", target)
+	}
+
+	_, opts := ParseTag(reflect.StructField{Name: "At", Tag: `json:"at,omitzero"`})
+	if opts != (TagOptions{OmitZero: true}) {
+		t.Fatalf("ParseTag() = %+v", opts)
+	}
+}
@@ -0,0 +1,76 @@
+package object
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var enums = struct {
+	sync.RWMutex
+	names map[reflect.Type]map[string]reflect.Value
+}{
+	names: map[reflect.Type]map[string]reflect.Value{},
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// RegisterEnum registers the string names of the values of the enum type
+// T, e.g. RegisterEnum(map[string]Color{"red": Red, "green": Green}), so
+// that string keys are converted to T when assigned to maps keyed by T.
+// Enum types implementing encoding.TextUnmarshaler don't need to be
+// registered. Registering T again replaces its names.
+func RegisterEnum[T comparable](names map[string]T) {
+	values := make(map[string]reflect.Value, len(names))
+	for name, value := range names {
+		values[name] = reflect.ValueOf(value)
+	}
+
+	enums.Lock()
+	defer enums.Unlock()
+	enums.names[reflect.TypeOf((*T)(nil)).Elem()] = values
+}
+
+func lookupEnum(typ reflect.Type) (map[string]reflect.Value, bool) {
+	enums.RLock()
+	defer enums.RUnlock()
+	names, ok := enums.names[typ]
+	return names, ok
+}
+
+// assignKey converts the map key sourceVal into keyVal. String keys are
+// converted to registered enums and encoding.TextUnmarshaler types,
+// non string keys to strings according to KeyStringification, other keys
+// are converted by the keyAssigner.
+func (a *assigner) assignKey(keyVal reflect.Value, sourceVal reflect.Value) error {
+	if sourceVal.IsValid() && sourceVal.Kind() == reflect.Interface {
+		sourceVal = sourceVal.Elem()
+	}
+
+	if keyType := keyVal.Type(); sourceVal.IsValid() && sourceVal.Kind() == reflect.String && sourceVal.Type() != keyType {
+
+		if names, ok := lookupEnum(keyType); ok {
+			value, ok := names[sourceVal.String()]
+			if !ok {
+				return fmt.Errorf("unknown %s '%s'", keyType, sourceVal.String())
+			}
+			keyVal.Set(value)
+			return nil
+		}
+
+		if keyVal.CanAddr() && keyVal.Addr().Type().Implements(textUnmarshalerType) {
+			unmarshaler := keyVal.Addr().Interface().(encoding.TextUnmarshaler)
+			if err := unmarshaler.UnmarshalText([]byte(sourceVal.String())); err != nil {
+				return fmt.Errorf("error decoding %s '%s': %w", keyType, sourceVal.String(), err)
+			}
+			return nil
+		}
+	}
+
+	if ok, err := a.assignStringKey(keyVal, sourceVal); ok {
+		return err
+	}
+
+	return a.keyAssigner.assign(keyVal, "", sourceVal, "")
+}
@@ -0,0 +1,70 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testColor int
+
+const (
+	testRed testColor = iota + 1
+	testGreen
+)
+
+type testLevel int
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("invalid level")
+	}
+	return nil
+}
+
+func TestAssign_EnumMapKeys(t *testing.T) {
+	t.Parallel()
+
+	RegisterEnum(map[string]testColor{"red": testRed, "green": testGreen})
+
+	type Target struct {
+		Colors map[testColor]int    `json:"colors"`
+		Levels map[testLevel]string `json:"levels"`
+	}
+
+	input := map[string]any{
+		"colors": map[string]any{"red": 1, "green": 2},
+		"levels": map[string]string{"low": "a", "high": "b"},
+	}
+
+	var result Target
+	if err := Assign(&result, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Target{
+		Colors: map[testColor]int{testRed: 1, testGreen: 2},
+		Levels: map[testLevel]string{1: "a", 2: "b"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	var bad Target
+	err := Assign(&bad, map[string]any{
+		"colors": map[string]any{"blue": 3},
+		"levels": map[string]any{"medium": "c"},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "unknown object.testColor 'blue'") || !strings.Contains(err.Error(), "invalid level") {
+		t.Fatalf("bad error: %s", err)
+	}
+}
@@ -3,6 +3,7 @@ package object
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 )
@@ -11,6 +12,16 @@ import (
 // errors that occur in the course of a single decode.
 type Error struct {
 	Errors []string
+
+	// Causes holds the errors behind Errors, in the same order. They are
+	// typed when the cause is known, e.g. *UnconvertibleTypeError, and can
+	// be inspected with errors.As.
+	Causes []error
+
+	// Collection holds the errors of individual slice, array and map
+	// elements, at any depth, so failures can be mapped back to input
+	// rows or keys programmatically.
+	Collection []*CollectionError
 }
 
 func (e *Error) Error() string {
@@ -19,12 +30,85 @@ func (e *Error) Error() string {
 		points[i] = fmt.Sprintf("* %s", err)
 	}
 
-	sort.Strings(points)
+	sort.SliceStable(points, func(i, j int) bool {
+		return pathLess(points[i], points[j])
+	})
 	return fmt.Sprintf(
 		"%d error(s) decoding:\n\n%s",
 		len(e.Errors), strings.Join(points, "\n"))
 }
 
+// Unwrap returns the errors behind the Error, so errors.Is and errors.As
+// match any of them.
+func (e *Error) Unwrap() []error {
+	return e.WrappedErrors()
+}
+
+// Is reports whether any of the errors behind the Error matches target.
+// Together with As it lets errors.Is and errors.As match them before Go
+// 1.20, which ignores Unwrap methods returning several errors.
+func (e *Error) Is(target error) bool {
+	for _, err := range e.WrappedErrors() {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors behind the Error that matches target,
+// see Is.
+func (e *Error) As(target any) bool {
+	for _, err := range e.WrappedErrors() {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldErrors returns the errors behind the Error whose path is known, as
+// FieldErrors, so failures can be mapped back to the fields of the input,
+// see FieldError.Segments.
+func (e *Error) FieldErrors() []*FieldError {
+	if e == nil {
+		return nil
+	}
+
+	result := make([]*FieldError, 0, len(e.Causes))
+	for _, cause := range e.Causes {
+		if field, ok := asFieldError(cause); ok {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// asFieldError returns err as a FieldError when its path is known.
+func asFieldError(err error) (*FieldError, bool) {
+	switch e := err.(type) {
+	case *FieldError:
+		return e, true
+	case *UnconvertibleTypeError:
+		return &FieldError{Path: e.Path, Err: e}, true
+	case *OverflowError:
+		return &FieldError{Path: e.Path, Err: e}, true
+	case *UnsupportedTypeError:
+		return &FieldError{Path: e.Path, Err: e}, true
+	case *UnusedKeyError:
+		return &FieldError{Path: e.Path, Err: e}, true
+	case *RangeError:
+		return &FieldError{Path: e.Path, Err: e}, true
+	case *PanicError:
+		return &FieldError{Path: e.Path, Err: e}, true
+	case *LimitError:
+		return &FieldError{Path: e.Path, Err: e}, true
+	case *ValidationError:
+		return &FieldError{Path: e.Path, Err: e}, true
+	}
+	return nil, false
+}
+
 // WrappedErrors implements the errwrap.Wrapper interface to make this
 // return value more useful with the errwrap and go-multierror libraries.
 func (e *Error) WrappedErrors() []error {
@@ -32,6 +116,10 @@ func (e *Error) WrappedErrors() []error {
 		return nil
 	}
 
+	if len(e.Causes) == len(e.Errors) {
+		return e.Causes
+	}
+
 	result := make([]error, len(e.Errors))
 	for i, e := range e.Errors {
 		result[i] = errors.New(e)
@@ -40,11 +128,298 @@ func (e *Error) WrappedErrors() []error {
 	return result
 }
 
-func appendErrors(errors []string, err error) []string {
+// newError returns an Error holding errors and collection sorted by path,
+// so that errors collected while iterating maps are reported in the same
+// order from run to run.
+func newError(causes []error, collection []*CollectionError) *Error {
+	errors := make([]string, len(causes))
+	for i, err := range causes {
+		errors[i] = err.Error()
+	}
+
+	sort.Sort(errorsByPath{errors, causes})
+	sort.SliceStable(collection, func(i, j int) bool {
+		return pathLess(collection[i].Path, collection[j].Path)
+	})
+	return &Error{Errors: errors, Causes: causes, Collection: collection}
+}
+
+// errorsByPath sorts error messages, and their causes alongside, by path.
+type errorsByPath struct {
+	errors []string
+	causes []error
+}
+
+func (e errorsByPath) Len() int { return len(e.errors) }
+
+func (e errorsByPath) Less(i, j int) bool { return pathLess(e.errors[i], e.errors[j]) }
+
+func (e errorsByPath) Swap(i, j int) {
+	e.errors[i], e.errors[j] = e.errors[j], e.errors[i]
+	e.causes[i], e.causes[j] = e.causes[j], e.causes[i]
+}
+
+// pathLess orders paths, and messages starting with them, lexically except
+// for runs of digits which are compared by value, so "Items[2]" sorts
+// before "Items[10]".
+func pathLess(a, b string) bool {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, nb := digitsLen(a), digitsLen(b)
+			da, db := strings.TrimLeft(a[:na], "0"), strings.TrimLeft(b[:nb], "0")
+			if len(da) != len(db) {
+				return len(da) < len(db)
+			}
+			if da != db {
+				return da < db
+			}
+			a, b = a[na:], b[nb:]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// digitsLen returns the length of the run of digits s starts with.
+func digitsLen(s string) int {
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	return n
+}
+
+// CollectionError is the error of a single slice, array or map element.
+type CollectionError struct {
+	// Path is the full path of the element, e.g. "Items[2]".
+	Path string
+
+	// Index is the position of the element in a slice or array,
+	// or -1 for map elements.
+	Index int
+
+	// Key is the key of the element in a map, or "" for slice and
+	// array elements.
+	Key string
+
+	// Err is the error that occurred while decoding the element.
+	Err error
+}
+
+func (e *CollectionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CollectionError) Unwrap() error {
+	return e.Err
+}
+
+// appendElementError appends the error of a single collection element,
+// followed by the collection errors nested in it.
+func appendElementError(errors []*CollectionError, path metaKey, index int, key string, err error) []*CollectionError {
+	errors = append(errors, &CollectionError{
+		Path:  path.String(),
+		Index: index,
+		Key:   key,
+		Err:   err,
+	})
+	return appendCollectionErrors(errors, err)
+}
+
+// appendCollectionErrors appends the collection errors carried by err.
+func appendCollectionErrors(errors []*CollectionError, err error) []*CollectionError {
+	if e, ok := err.(*Error); ok {
+		return append(errors, e.Collection...)
+	}
+	return errors
+}
+
+func appendErrors(errors []error, err error) []error {
 	switch e := err.(type) {
 	case *Error:
-		return append(errors, e.Errors...)
+		return append(errors, e.WrappedErrors()...)
 	default:
-		return append(errors, e.Error())
+		return append(errors, e)
+	}
+}
+
+// FieldError is an error attached to the path of the value that caused
+// it, such as "Items[2].Name".
+type FieldError struct {
+	// Path is the full path of the value.
+	Path string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// NewFieldError returns err attached to path. Hooks and custom converters
+// can use it to report errors at a path of their choosing, errors they
+// return otherwise are attached to the path of the value being assigned.
+func NewFieldError(path string, err error) error {
+	return &FieldError{Path: path, Err: err}
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("'%s' %s", e.Path, e.Err)
+}
+
+// Segments returns the segments of Path, see SplitPath. Invalid paths have
+// no segments.
+func (e *FieldError) Segments() []PathSegment {
+	segments, err := SplitPath(e.Path)
+	if err != nil {
+		return nil
+	}
+	return segments
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is returned when Recover is enabled and assigning a value
+// panicked.
+type PanicError struct {
+	// Path is the full path of the innermost value being assigned.
+	Path string
+
+	// Value is the value the assignment panicked with.
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("'%s' panic: %v", e.Path, e.Value)
+}
+
+// RangeError is returned when SafeNumerics is enabled and a numeric
+// value cannot be represented by the target type without loss.
+type RangeError struct {
+	// Path is the full path of the target.
+	Path string
+
+	// Value is the source value that was rejected.
+	Value any
+
+	// Type is the type of the target field.
+	Type reflect.Type
+}
+
+func newRangeError(key metaKey, value any, typ reflect.Type) *RangeError {
+	return &RangeError{
+		Path:  key.String(),
+		Value: value,
+		Type:  typ,
 	}
 }
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf(
+		"'%s' value %v is out of range for type '%s'",
+		e.Path, e.Value, e.Type)
+}
+
+// UnconvertibleTypeError is returned when a source value cannot be
+// converted to the type of its target.
+type UnconvertibleTypeError struct {
+	// Path is the full path of the target.
+	Path string
+
+	// SourceType is the type of the source value.
+	SourceType reflect.Type
+
+	// TargetType is the type of the target.
+	TargetType reflect.Type
+
+	// Value is the source value.
+	Value any
+}
+
+func newUnconvertibleTypeError(key metaKey, targetType reflect.Type, sourceVal reflect.Value) *UnconvertibleTypeError {
+	return &UnconvertibleTypeError{
+		Path:       key.String(),
+		SourceType: sourceVal.Type(),
+		TargetType: targetType,
+		Value:      sourceVal.Interface(),
+	}
+}
+
+func (e *UnconvertibleTypeError) Error() string {
+	return fmt.Sprintf(
+		"'%s' expected type '%s', got unconvertible type '%s', value: '%v'",
+		e.Path, e.TargetType, e.SourceType, e.Value)
+}
+
+// OverflowError is returned when a negative number is assigned to an
+// unsigned target without WeaklyTypedInput.
+type OverflowError struct {
+	// Path is the full path of the target.
+	Path string
+
+	// Value is the source value.
+	Value any
+
+	// SourceType is the type of the source value.
+	SourceType reflect.Type
+
+	// TargetType is the type of the target.
+	TargetType reflect.Type
+}
+
+func newOverflowError(key metaKey, value any, targetType reflect.Type) *OverflowError {
+	return &OverflowError{
+		Path:       key.String(),
+		Value:      value,
+		SourceType: reflect.TypeOf(value),
+		TargetType: targetType,
+	}
+}
+
+func (e *OverflowError) Error() string {
+	if f, ok := e.Value.(float64); ok {
+		return fmt.Sprintf("cannot parse '%s', %f overflows uint", e.Path, f)
+	}
+	return fmt.Sprintf("cannot parse '%s', %v overflows uint", e.Path, e.Value)
+}
+
+// UnsupportedTypeError is returned when the type of a target cannot be
+// assigned to, such as channels.
+type UnsupportedTypeError struct {
+	// Path is the full path of the target.
+	Path string
+
+	// Type is the type of the target.
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("%s: unsupported type: %s", e.Path, e.Type.Kind())
+}
+
+// UnusedKeyError is returned when ErrorUnused is enabled and a source map
+// has keys that don't match any field of the target struct.
+type UnusedKeyError struct {
+	// Path is the full path of the target struct.
+	Path string
+
+	// Keys are the unused keys, sorted.
+	Keys []string
+
+	// SourceType is the type of the source map.
+	SourceType reflect.Type
+
+	// TargetType is the type of the target struct.
+	TargetType reflect.Type
+}
+
+func (e *UnusedKeyError) Error() string {
+	return fmt.Sprintf("'%s' has invalid keys: %s", e.Path, strings.Join(e.Keys, ", "))
+}
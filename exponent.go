@@ -0,0 +1,58 @@
+package object
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// isExponent reports whether str is written in scientific notation, such
+// as "1e3".
+func isExponent(str string) bool {
+	body := strings.ToLower(strings.TrimLeft(str, "+-"))
+	return !strings.HasPrefix(body, "0x") && strings.ContainsRune(body, 'e')
+}
+
+// parseExponent parses the scientific notation str into an integer. It
+// fails when the value is not integral, and reports false when it needs
+// more than bitSize bits, which is checked before the integer is built so
+// that huge exponents don't allocate huge integers.
+func parseExponent(str string, bitSize int) (*big.Int, bool, error) {
+	f, _, err := big.ParseFloat(str, 10, 256, big.ToNearestEven)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid number '%s'", str)
+	}
+	if !f.IsInt() {
+		return nil, false, fmt.Errorf("'%s' is not an integral value", str)
+	}
+	if f.MantExp(nil) > bitSize {
+		return nil, false, nil
+	}
+	i, _ := f.Int(nil)
+	return i, true, nil
+}
+
+// parseExponentInt parses the scientific notation str into an integer of
+// bitSize bits, see IntegerExponents.
+func parseExponentInt(str string, bitSize int) (int64, error) {
+	i, ok, err := parseExponent(str, bitSize)
+	if err != nil {
+		return 0, err
+	}
+	if !ok || i.BitLen() >= bitSize && !(i.Sign() < 0 && i.BitLen() == bitSize && i.TrailingZeroBits() == uint(bitSize-1)) {
+		return 0, fmt.Errorf("'%s' overflows int%d", str, bitSize)
+	}
+	return i.Int64(), nil
+}
+
+// parseExponentUint is like parseExponentInt for unsigned integers.
+func parseExponentUint(str string, bitSize int) (uint64, error) {
+	i, ok, err := parseExponent(str, bitSize)
+	if err != nil {
+		return 0, err
+	}
+	if !ok || i.Sign() < 0 || i.BitLen() > bitSize {
+		return 0, fmt.Errorf("'%s' overflows uint%d", str, bitSize)
+	}
+	return i.Uint64(), nil
+}
@@ -0,0 +1,152 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Bitmask is the constraint of the bit flag types registered with
+// RegisterFlags.
+type Bitmask interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// flag is a registered name of a bit flag type.
+type flag struct {
+	name string
+	bits uint64
+}
+
+// flagSets holds the registered flags. It is looked up for most values
+// assigned, so it holds a map[reflect.Type][]flag that is copied on write
+// and read without locking.
+var flagSets struct {
+	sync.Mutex
+	flags atomic.Value
+}
+
+// RegisterFlags registers the names of the bits of the bit flag type T,
+// e.g. RegisterFlags(map[string]Perm{"read": Read, "write": Write}), so
+// that lists of names such as ["read", "write"] are assigned to T as the
+// union of their bits, and values of T are assigned to slices and struct
+// fields to maps as the list of the names of their bits. Registering T
+// again replaces its names.
+func RegisterFlags[T Bitmask](names map[string]T) {
+	flags := make([]flag, 0, len(names))
+	for name, value := range names {
+		flags = append(flags, flag{name: name, bits: flagBits(reflect.ValueOf(value))})
+	}
+	sort.Slice(flags, func(i, j int) bool {
+		if flags[i].bits != flags[j].bits {
+			return flags[i].bits < flags[j].bits
+		}
+		return flags[i].name < flags[j].name
+	})
+
+	flagSets.Lock()
+	defer flagSets.Unlock()
+	current, _ := flagSets.flags.Load().(map[reflect.Type][]flag)
+	registered := make(map[reflect.Type][]flag, len(current)+1)
+	for typ, set := range current {
+		registered[typ] = set
+	}
+	registered[reflect.TypeOf((*T)(nil)).Elem()] = flags
+	flagSets.flags.Store(registered)
+}
+
+func lookupFlags(typ reflect.Type) ([]flag, bool) {
+	registered, _ := flagSets.flags.Load().(map[reflect.Type][]flag)
+	if len(registered) == 0 {
+		return nil, false
+	}
+	flags, ok := registered[typ]
+	return flags, ok
+}
+
+func flagBits(val reflect.Value) uint64 {
+	if isInt(val.Kind()) {
+		return uint64(val.Int())
+	}
+	return val.Uint()
+}
+
+// assignFlags assigns a name or a list of names to the registered bit
+// flag targetVal. It reports false for other sources, such as numbers,
+// which are assigned as usual.
+func (a *assigner) assignFlags(flags []flag, targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value) (bool, error) {
+	source := indirectValue(sourceVal)
+	if !source.IsValid() {
+		return false, nil
+	}
+
+	var names []string
+	switch {
+	case isString(source.Kind()):
+		names = []string{source.String()}
+	case isArraySlice(source.Kind()):
+		for i := 0; i < source.Len(); i++ {
+			elem := indirectValue(source.Index(i))
+			if !elem.IsValid() || !isString(elem.Kind()) {
+				return false, nil
+			}
+			names = append(names, elem.String())
+		}
+	default:
+		return false, nil
+	}
+
+	var bits uint64
+	for _, name := range names {
+		found := false
+		for _, f := range flags {
+			if f.name == name {
+				bits |= f.bits
+				found = true
+				break
+			}
+		}
+		if !found {
+			return true, fmt.Errorf("'%s' unknown %s flag '%s'", targetKey.String(), targetVal.Type(), name)
+		}
+	}
+
+	if isInt(targetVal.Kind()) {
+		targetVal.SetInt(int64(bits))
+	} else {
+		targetVal.SetUint(bits)
+	}
+	return true, nil
+}
+
+// flagNames returns the names of the bits set in val, of a registered bit
+// flag type, in increasing order of their values. It reports false for
+// other values and for masks with bits that have no name, which are kept
+// as numbers.
+func flagNames(val reflect.Value) (reflect.Value, bool) {
+	val = indirectValue(val)
+	if !val.IsValid() {
+		return val, false
+	}
+	flags, ok := lookupFlags(val.Type())
+	if !ok {
+		return val, false
+	}
+
+	mask := flagBits(val)
+	names := make([]string, 0)
+	var named uint64
+	for _, f := range flags {
+		if f.bits != 0 && mask&f.bits == f.bits {
+			names = append(names, f.name)
+			named |= f.bits
+		}
+	}
+	if named != mask {
+		return val, false
+	}
+	return reflect.ValueOf(names), true
+}
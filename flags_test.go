@@ -0,0 +1,66 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testPerm uint8
+
+const (
+	testRead testPerm = 1 << iota
+	testWrite
+	testExec
+)
+
+func TestAssign_Flags(t *testing.T) {
+	t.Parallel()
+
+	RegisterFlags(map[string]testPerm{"read": testRead, "write": testWrite, "exec": testExec})
+
+	type User struct {
+		Name  string   `json:"name"`
+		Perms testPerm `json:"perms"`
+	}
+
+	var user User
+	if err := Assign(&user, map[string]any{"name": "ann", "perms": []any{"read", "write"}}); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if user.Perms != testRead|testWrite {
+		t.Fatalf("Assign() perms = %b", user.Perms)
+	}
+
+	// Single names and numbers are accepted too.
+	if err := Assign(&user, map[string]any{"perms": "exec"}); err != nil || user.Perms != testExec {
+		t.Fatalf("Assign() perms = %b, %v", user.Perms, err)
+	}
+	if err := Assign(&user, map[string]any{"perms": 3}); err != nil || user.Perms != testRead|testWrite {
+		t.Fatalf("Assign() perms = %b, %v", user.Perms, err)
+	}
+
+	err := Assign(&user, map[string]any{"perms": []string{"read", "admin"}})
+	if err == nil {
+		t.Fatalf("Assign() expected error for unknown flag")
+	}
+
+	out := map[string]any{}
+	if err := Assign(&out, User{Name: "ann", Perms: testExec | testRead}); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	want := map[string]any{"name": "ann", "perms": []string{"read", "exec"}}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("Assign() = %#v, want %#v", out, want)
+	}
+
+	// Masks with unnamed bits are kept as numbers.
+	out = map[string]any{}
+	if err := Assign(&out, User{Perms: 1 << 7}); err != nil || out["perms"] != testPerm(1<<7) {
+		t.Fatalf("Assign() = %#v, %v", out, err)
+	}
+
+	var names []string
+	if err := Assign(&names, testRead|testWrite); err != nil || !reflect.DeepEqual(names, []string{"read", "write"}) {
+		t.Fatalf("Assign() = %v, %v", names, err)
+	}
+}
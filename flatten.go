@@ -0,0 +1,238 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FlattenFormat is the syntax of the keys produced by Flatten and read by
+// Unflatten.
+type FlattenFormat struct {
+	// Separator joins the keys of nested values, "." if empty.
+	Separator string
+
+	// IndexSegments writes slice and array indexes as keys of their own
+	// (e.g. "items_0_name") instead of in brackets (e.g. "items[0].name").
+	IndexSegments bool
+
+	// UpperCase writes keys in upper case. Unflatten reads them in lower
+	// case, use a case insensitive MatchName to assign them to structs.
+	UpperCase bool
+}
+
+var (
+	// PathFormat produces keys such as "items[0].name", the path syntax of
+	// Document: keys holding "." or brackets are bracketed, e.g.
+	// "labels[a.b]".
+	PathFormat = FlattenFormat{Separator: "."}
+
+	// EnvFormat produces environment variable style keys such as
+	// "ITEMS_0_NAME".
+	EnvFormat = FlattenFormat{Separator: "_", IndexSegments: true, UpperCase: true}
+)
+
+func (f FlattenFormat) separator() string {
+	if f.Separator == "" {
+		return "."
+	}
+	return f.Separator
+}
+
+// Flatten returns the leaves of v, a map or a struct, keyed by their path
+// in format. Structs are converted to maps like Assign does, empty maps,
+// slices and arrays are leaves.
+func Flatten(v any, format FlattenFormat, configs ...func(c *AssignConfig)) (map[string]any, error) {
+	sourceVal, err := genericSource(v, configs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]any{}
+	format.walkLeaves(sourceVal, "", func(path string, value any) bool {
+		result[path] = value
+		return true
+	})
+	return result, nil
+}
+
+// walkLeaves yields the leaves of val below path, keyed by their path in
+// the format. Maps are visited in key order and structs through their keys
+// as Assign would produce them. It reports false once yield asked to stop.
+func (f FlattenFormat) walkLeaves(val reflect.Value, path string, yield func(string, any) bool) bool {
+	leaf := val
+	val = indirectValue(val)
+	if !val.IsValid() {
+		if !leaf.IsValid() {
+			return yield(path, nil)
+		}
+		return yield(path, leaf.Interface())
+	}
+
+	switch val.Kind() {
+	case reflect.Map:
+		if val.Len() == 0 {
+			break
+		}
+		keys := val.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return mapKeyString(keys[i]) < mapKeyString(keys[j])
+		})
+		for _, key := range keys {
+			if !f.walkLeaves(val.MapIndex(key), f.join(path, mapKeyString(key)), yield) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if val.Len() == 0 || val.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		for i := 0; i < val.Len(); i++ {
+			if !f.walkLeaves(val.Index(i), f.index(path, i), yield) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		if _, ok := asDecimal(val); ok || !holdsStructs(val.Type()) {
+			break
+		}
+		fields := map[string]any{}
+		if err := defaultAssigner.assignMapFromStruct(reflect.ValueOf(fields), "", val, ""); err != nil || len(fields) == 0 {
+			break
+		}
+		return f.walkLeaves(reflect.ValueOf(fields), path, yield)
+	}
+
+	return yield(path, leaf.Interface())
+}
+
+// documentPaths reports whether the format writes the path syntax of
+// Document, which brackets keys holding separators.
+func (f FlattenFormat) documentPaths() bool {
+	return f.separator() == "." && !f.IndexSegments
+}
+
+func (f FlattenFormat) join(path, key string) string {
+	if f.UpperCase {
+		key = strings.ToUpper(key)
+	}
+	if f.documentPaths() {
+		return joinPath(path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + f.separator() + key
+}
+
+func (f FlattenFormat) index(path string, i int) string {
+	if f.IndexSegments {
+		return f.join(path, strconv.Itoa(i))
+	}
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// Unflatten is the reverse of Flatten: it nests the values of flat,
+// keyed by their path in format, into maps and slices. Numeric keys
+// counting from 0 are slice indexes, so maps with such keys come back as
+// slices, other numeric keys are map keys. Slice indexes that skip
+// elements are an error.
+func Unflatten[V any](flat map[string]V, format FlattenFormat) (map[string]any, error) {
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return pathLess(keys[i], keys[j])
+	})
+
+	result := map[string]any{}
+	for _, key := range keys {
+		segments, err := format.parse(key)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := setPath(result, segments, any(flat[key]), false); err != nil {
+			return nil, fmt.Errorf("'%s' %w", key, err)
+		}
+	}
+	return result, nil
+}
+
+func (f FlattenFormat) parse(key string) ([]pathSegment, error) {
+	if key == "" {
+		return nil, fmt.Errorf("invalid key '': empty key")
+	}
+	if f.UpperCase {
+		key = strings.ToLower(key)
+	}
+
+	if f.documentPaths() {
+		segments, err := parsePath(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key '%s': %w", key, err)
+		}
+		if segments[0].isIndex {
+			return nil, fmt.Errorf("invalid key '%s': missing key before index", key)
+		}
+		return segments, nil
+	}
+
+	var segments []pathSegment
+	for i, part := range strings.Split(key, f.separator()) {
+		if part == "" {
+			return nil, fmt.Errorf("invalid key '%s': empty segment", key)
+		}
+
+		if f.IndexSegments {
+			if index, err := strconv.Atoi(part); err == nil && i > 0 {
+				segments = append(segments, pathSegment{key: part, index: index, isIndex: true})
+				continue
+			}
+			segments = append(segments, pathSegment{key: part})
+			continue
+		}
+
+		parsed, err := parseIndexes(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key '%s': %w", key, err)
+		}
+		if parsed[0].isIndex && i == 0 {
+			return nil, fmt.Errorf("invalid key '%s': missing key before index", key)
+		}
+		segments = append(segments, parsed...)
+	}
+	return segments, nil
+}
+
+// parseIndexes splits a segment of a key such as "items[0][1]" into the
+// key and its bracketed indexes. Unlike in document paths, "." is part of
+// the key.
+func parseIndexes(part string) ([]pathSegment, error) {
+	end := strings.IndexByte(part, '[')
+	if end < 0 {
+		return []pathSegment{{key: part}}, nil
+	}
+
+	var segments []pathSegment
+	if end > 0 {
+		segments = append(segments, pathSegment{key: part[:end]})
+	}
+	for rest := part[end:]; rest != ""; {
+		closing := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || closing < 0 {
+			return nil, fmt.Errorf("malformed index in '%s'", part)
+		}
+		index, err := strconv.Atoi(rest[1:closing])
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("'%s' is not a valid slice index", rest[:closing+1])
+		}
+		segments = append(segments, pathSegment{key: rest[1:closing], index: index, isIndex: true})
+		rest = rest[closing+1:]
+	}
+	return segments, nil
+}
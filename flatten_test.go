@@ -0,0 +1,143 @@
+package object
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		Name string
+		Tags []string
+	}
+	type Config struct {
+		MaxConns int
+		Items    []Item
+		Labels   map[string]string
+	}
+
+	config := Config{
+		MaxConns: 10,
+		Items:    []Item{{Name: "a", Tags: []string{"x", "y"}}, {Name: "b"}},
+		Labels:   map[string]string{"env": "dev"},
+	}
+
+	flat, err := Flatten(config, PathFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]any{
+		"maxConns":         10,
+		"items[0].name":    "a",
+		"items[0].tags[0]": "x",
+		"items[0].tags[1]": "y",
+		"items[1].name":    "b",
+		"items[1].tags":    []string(nil),
+		"labels.env":       "dev",
+	}
+	if !reflect.DeepEqual(flat, expected) {
+		t.Fatalf("expected %v, got %v", expected, flat)
+	}
+
+	env, err := Flatten(config, EnvFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if env["ITEMS_0_TAGS_1"] != "y" || env["MAXCONNS"] != 10 || env["LABELS_ENV"] != "dev" {
+		t.Fatalf("bad: %v", env)
+	}
+
+	// Unflatten reads both formats back.
+	for _, test := range []struct {
+		format FlattenFormat
+		flat   map[string]any
+	}{
+		{PathFormat, flat},
+		{EnvFormat, env},
+	} {
+		nested, err := Unflatten(test.flat, test.format)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		var result Config
+		err = Assign(&result, nested, func(c *AssignConfig) {
+			c.MatchName = strings.EqualFold
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(result, config) {
+			t.Fatalf("expected %+v, got %+v", config, result)
+		}
+	}
+
+	// Environment variables are strings.
+	nested, err := Unflatten(map[string]string{"SERVERS_1_HOST": "b", "SERVERS_0_HOST": "a"}, EnvFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expectedNested := map[string]any{
+		"servers": []any{map[string]any{"host": "a"}, map[string]any{"host": "b"}},
+	}
+	if !reflect.DeepEqual(nested, expectedNested) {
+		t.Fatalf("expected %v, got %v", expectedNested, nested)
+	}
+
+	// Keys holding separators are bracketed like in Document paths.
+	dotted := map[string]any{"a.b": 1, "c": map[string]any{"d.e": []any{2}}}
+	flat, err = Flatten(dotted, PathFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expectedFlat := map[string]any{"[a.b]": 1, "c[d.e][0]": 2}
+	if !reflect.DeepEqual(flat, expectedFlat) {
+		t.Fatalf("expected %v, got %v", expectedFlat, flat)
+	}
+	nested, err = Unflatten(flat, PathFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(nested, dotted) {
+		t.Fatalf("expected %v, got %v", dotted, nested)
+	}
+
+	for _, invalid := range []map[string]any{{"a": 1, "a.b": 2}, {"a..b": 1}, {"[0]": 1}} {
+		if _, err := Unflatten(invalid, PathFormat); err == nil {
+			t.Fatalf("expected an error for %v", invalid)
+		}
+	}
+
+	// Custom separators only split keys on the separator and brackets
+	underscored := FlattenFormat{Separator: "_"}
+	items := map[string]any{"items": []any{map[string]any{"a.b": 1}}}
+	flat, err = Flatten(items, underscored)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(flat, map[string]any{"items[0]_a.b": 1}) {
+		t.Fatalf("bad flat: %v", flat)
+	}
+	nested, err = Unflatten(flat, underscored)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(nested, items) {
+		t.Fatalf("expected %v, got %v", items, nested)
+	}
+
+	// Slices start at index 0 and can't skip elements
+	nested, err = Unflatten(map[string]string{"CODES_404": "not found"}, EnvFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(nested, map[string]any{"codes": map[string]any{"404": "not found"}}) {
+		t.Fatalf("bad codes: %v", nested)
+	}
+	if _, err := Unflatten(map[string]string{"ITEMS_0": "a", "ITEMS_999999999": "b"}, EnvFormat); err == nil {
+		t.Fatal("expected an error for a sparse index")
+	}
+}
@@ -0,0 +1,95 @@
+package object
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type fuzzTarget struct {
+	Vstring string
+	Vint    int
+	Vuint   uint8
+	Vfloat  float32
+	Vbool   bool
+	Vptr    *int
+	Vslice  []string
+	Varray  [2]int
+	Vmap    map[string]int
+	Vany    any
+	Nested  *fuzzTarget
+	Items   []fuzzTarget
+	KVs     []KV
+	Embedded
+}
+
+func fuzzSeeds(f *testing.F) {
+	seeds := []string{
+		`{}`,
+		`[]`,
+		`null`,
+		`"string"`,
+		`{"vstring": 1, "vint": "2", "vuint": -1, "vfloat": 1e400, "vbool": "yes"}`,
+		`{"nested": {"nested": {"items": [{"vmap": {"a": "b"}}, null, 1]}}}`,
+		`{"vslice": {"0": 1}, "varray": [1, 2, 3], "vmap": [{"a": 1}, {"b": 2}]}`,
+		`{"vany": {"a": [1, {"b": null}]}, "kVs": {"a": 1}, "vptr": null}`,
+		`[{"a": 1}, [1, 2], "x", null, 1.5]`,
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+}
+
+func FuzzAssign(f *testing.F) {
+	fuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var source any
+		if err := json.Unmarshal(data, &source); err != nil {
+			return
+		}
+
+		for _, weak := range []bool{false, true} {
+			config := func(c *AssignConfig) {
+				c.WeaklyTypedInput = weak
+			}
+
+			var structTarget fuzzTarget
+			_ = Assign(&structTarget, source, config)
+
+			var mapTarget map[string]any
+			_ = Assign(&mapTarget, source, config)
+
+			var sliceTarget []any
+			_ = Assign(&sliceTarget, source, config)
+
+			var arrayTarget [3]map[string]string
+			_ = Assign(&arrayTarget, source, config)
+
+			var anyTarget any
+			_ = Assign(&anyTarget, source, config)
+
+			// Round trip the decoded struct back into a map.
+			_ = Assign(&mapTarget, structTarget, config)
+		}
+	})
+}
+
+func FuzzDocument(f *testing.F) {
+	fuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var source map[string]any
+		if err := json.Unmarshal(data, &source); err != nil {
+			return
+		}
+
+		doc := NewDocument(nil)
+		_ = doc.Merge(source)
+		for key := range source {
+			doc.Get(key + ".a[0]")
+			_ = doc.Set(key+"[1].b", 1)
+			doc.Delete(key + ".a")
+		}
+		_ = NormalizeKeys(source)
+	})
+}
@@ -0,0 +1,92 @@
+package object
+
+import "reflect"
+
+// AssignerFrom is implemented by types with generated assignment code, see
+// cmd/objectgen. AssignFrom fills the receiver from source without
+// reflection, exactly like Assign would with the default configuration.
+type AssignerFrom interface {
+	AssignFrom(source map[string]any) error
+}
+
+// AssignerTo is implemented by types with generated assignment code, see
+// cmd/objectgen. AssignTo writes the fields of the receiver into target
+// without reflection, exactly like Assign would with the default
+// configuration.
+type AssignerTo interface {
+	AssignTo(target map[string]any) error
+}
+
+var (
+	assignerFromType = reflect.TypeOf((*AssignerFrom)(nil)).Elem()
+	assignerToType   = reflect.TypeOf((*AssignerTo)(nil)).Elem()
+	anyMapType       = reflect.TypeOf(map[string]any(nil))
+)
+
+// generatedOptions are the options checked by usesGenerated itself.
+var generatedOptions = configFields("TagName", "Converter")
+
+// usesGenerated reports whether generated assignment code can be preferred
+// with config. Generated code bakes in the default tag name and key
+// converter, so any other option falls back to reflection.
+func usesGenerated(config *AssignConfig) bool {
+	if config.TagName != "json" || config.Converter == nil ||
+		reflect.ValueOf(config.Converter).Pointer() != reflect.ValueOf(toLowerCamel).Pointer() {
+		return false
+	}
+
+	return hasDefaultsExcept(config, generatedOptions)
+}
+
+// assignGenerated assigns through generated AssignFrom and AssignTo methods
+// when the target and source have them. It reports whether it did.
+func (a *assigner) assignGenerated(targetVal reflect.Value, sourceVal reflect.Value) (bool, error) {
+	if targetVal.Kind() == reflect.Struct && sourceVal.Type() == anyMapType &&
+		targetVal.CanAddr() && reflect.PointerTo(targetVal.Type()).Implements(assignerFromType) {
+		return true, targetVal.Addr().Interface().(AssignerFrom).AssignFrom(sourceVal.Interface().(map[string]any))
+	}
+
+	if targetVal.Type() == anyMapType && targetVal.CanSet() && sourceVal.Type().Implements(assignerToType) {
+		if isPtrAble(sourceVal.Kind()) && sourceVal.IsNil() {
+			return false, nil
+		}
+		if targetVal.IsNil() {
+			targetVal.Set(reflect.MakeMap(anyMapType))
+		}
+		return true, sourceVal.Interface().(AssignerTo).AssignTo(targetVal.Interface().(map[string]any))
+	}
+
+	return false, nil
+}
+
+// withoutGenerated returns a copy of a that always uses reflection.
+func (a *assigner) withoutGenerated() *assigner {
+	as := *a
+	as.generated = false
+	return &as
+}
+
+// AssignField assigns source to the field pointed to by target with the
+// default configuration. Generated AssignFrom methods call it for values
+// that can't be assigned directly; name and key are the field name and map
+// key reported in errors.
+func AssignField(target any, name, key string, source any) error {
+	return defaultAssigner.assign(reflect.ValueOf(target).Elem(), metaKey(name), reflect.ValueOf(source), metaKey(key))
+}
+
+// JoinErrors combines the errors returned by AssignField into a single
+// *Error, or returns nil if there are none.
+func JoinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	errors := make([]error, 0, len(errs))
+	collection := make([]*CollectionError, 0)
+	for _, err := range errs {
+		errors = appendErrors(errors, err)
+		collection = appendCollectionErrors(collection, err)
+	}
+
+	return newError(errors, collection)
+}
@@ -0,0 +1,141 @@
+package object
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// generatedPerson has the methods objectgen generates for it, plus a
+// counter of generated calls.
+type generatedPerson struct {
+	Name   string
+	Age    int `json:",omitempty"`
+	Emails []string
+
+	calls int
+}
+
+func (t *generatedPerson) AssignFrom(source map[string]any) error {
+	t.calls++
+	var errs []error
+	if v, ok := source["name"]; ok {
+		if tv, ok := v.(string); ok {
+			t.Name = tv
+		} else if err := AssignField(&t.Name, "Name", "name", v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if v, ok := source["age"]; ok {
+		if tv, ok := v.(int); ok {
+			t.Age = tv
+		} else if err := AssignField(&t.Age, "Age", "age", v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if v, ok := source["emails"]; ok {
+		if err := AssignField(&t.Emails, "Emails", "emails", v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return JoinErrors(errs)
+}
+
+func (t generatedPerson) AssignTo(target map[string]any) error {
+	target["name"] = t.Name
+	if t.Age != 0 {
+		target["age"] = t.Age
+	}
+	target["emails"] = t.Emails
+	target["generated"] = true
+	return nil
+}
+
+func TestAssign_Generated(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]any{
+		"name":   "gopher",
+		"age":    13,
+		"emails": []string{"a", "b"},
+	}
+
+	var result generatedPerson
+	if err := Assign(&result, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.calls != 1 {
+		t.Fatalf("expected the generated AssignFrom to be used")
+	}
+	if result.Name != "gopher" || result.Age != 13 || !reflect.DeepEqual(result.Emails, []string{"a", "b"}) {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	actual := map[string]any{}
+	if err := Assign(&actual, result); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual["generated"] != true || actual["name"] != "gopher" {
+		t.Fatalf("expected the generated AssignTo to be used, got %#v", actual)
+	}
+}
+
+func TestAssign_GeneratedFallback(t *testing.T) {
+	t.Parallel()
+
+	weak := func(c *AssignConfig) {
+		c.WeaklyTypedInput = true
+	}
+
+	var result generatedPerson
+	if err := Assign(&result, map[string]any{"age": "13"}, weak); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.calls != 0 || result.Age != 13 {
+		t.Fatalf("expected reflection to be used with options, got %#v", result)
+	}
+
+	actual := map[string]any{}
+	if err := Assign(&actual, result, weak); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, exist := actual["generated"]; exist {
+		t.Fatalf("expected reflection to be used with options, got %#v", actual)
+	}
+}
+
+func TestAssign_GeneratedErrors(t *testing.T) {
+	t.Parallel()
+
+	var result generatedPerson
+	err := Assign(&result, map[string]any{"name": 1, "age": "x"})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	derr, ok := err.(*Error)
+	if !ok || len(derr.Errors) != 2 {
+		t.Fatalf("expected two errors, got %#v", err)
+	}
+	if !strings.Contains(err.Error(), "'Age' expected type 'int'") ||
+		!strings.Contains(err.Error(), "'Name' expected type 'string'") {
+		t.Fatalf("bad error: %s", err)
+	}
+}
+
+func TestAssign_GeneratedErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	type Group struct {
+		People []generatedPerson
+	}
+
+	var generated []Group
+	err := Assign(&generated, []any{map[string]any{"people": []any{map[string]any{"name": "a", "age": "x"}}}})
+	if err == nil || !strings.Contains(err.Error(), "'[0].People[0].Age' expected type 'int'") {
+		t.Fatalf("expected the full path of the field, got %v", err)
+	}
+	if generated[0].People[0].Name != "a" {
+		t.Fatalf("expected the valid fields to be assigned, got %#v", generated)
+	}
+}
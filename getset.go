@@ -0,0 +1,152 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// GetPath returns the value at path (e.g. "a.b[2].c") in obj. Paths go
+// through pointers, interfaces, maps, slices, arrays and structs, whose
+// fields are named by their key, as Assign would produce it, or by their
+// Go name. Map keys are converted to the key type of the map.
+func GetPath(obj any, path string, configs ...func(c *AssignConfig)) (any, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	a := defaultAssigner
+	if len(configs) > 0 {
+		a = a.withConfig(configs...)
+	}
+
+	val, ok := a.lookupPath(reflect.ValueOf(obj), segments)
+	if !ok {
+		return nil, fmt.Errorf("path '%s' not found", path)
+	}
+	if !val.IsValid() {
+		return nil, nil
+	}
+	return val.Interface(), nil
+}
+
+// SetPath assigns value to the value at path in obj, which must be a
+// pointer, see GetPath. The value is assigned like Assign would assign it,
+// so it is converted to the type at path. Nil pointers, maps and
+// interfaces along the path are allocated and slices are grown to fit the
+// index.
+func SetPath(obj any, path string, value any, configs ...func(c *AssignConfig)) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	objVal := reflect.ValueOf(obj)
+	if objVal.Kind() != reflect.Ptr || objVal.IsNil() {
+		return errors.New("target must be a pointer")
+	}
+
+	a := defaultAssigner
+	if len(configs) > 0 {
+		a = a.withConfig(configs...)
+	}
+
+	return a.setPath(objVal.Elem(), "", segments, reflect.ValueOf(value))
+}
+
+// setPath assigns sourceVal to the value at segments below the settable
+// value targetVal.
+func (a *assigner) setPath(targetVal reflect.Value, targetKey metaKey, segments []pathSegment, sourceVal reflect.Value) error {
+	if len(segments) == 0 {
+		return a.assign(targetVal, targetKey, sourceVal, "")
+	}
+
+	seg := segments[0]
+	switch targetVal.Kind() {
+	case reflect.Ptr:
+		if targetVal.IsNil() {
+			targetVal.Set(reflect.New(targetVal.Type().Elem()))
+		}
+		return a.setPath(targetVal.Elem(), targetKey, segments, sourceVal)
+
+	case reflect.Interface:
+		// Values held by interfaces aren't addressable, they are copied,
+		// updated and stored back.
+		var elem reflect.Value
+		switch {
+		case !targetVal.IsNil():
+			elem = reflect.New(targetVal.Elem().Type()).Elem()
+			elem.Set(targetVal.Elem())
+		case seg.isIndex:
+			elem = reflect.New(reflect.TypeOf([]any{})).Elem()
+		default:
+			elem = reflect.New(reflect.TypeOf(map[string]any{})).Elem()
+		}
+		if err := a.setPath(elem, targetKey, segments, sourceVal); err != nil {
+			return err
+		}
+		if !elem.Type().AssignableTo(targetVal.Type()) {
+			return fmt.Errorf("'%s' cannot store '%s' in '%s'", targetKey, elem.Type(), targetVal.Type())
+		}
+		targetVal.Set(elem)
+		return nil
+
+	case reflect.Map:
+		key := reflect.New(targetVal.Type().Key()).Elem()
+		if err := a.assignKey(key, reflect.ValueOf(seg.key)); err != nil {
+			return fmt.Errorf("'%s' invalid key '%s': %w", targetKey, seg.key, err)
+		}
+		if targetVal.IsNil() {
+			targetVal.Set(reflect.MakeMap(targetVal.Type()))
+		}
+		// The leaf replaces the existing value unless Assign would merge
+		// them, e.g. values held by a map[string]any are replaced.
+		elem := reflect.New(targetVal.Type().Elem()).Elem()
+		if existing := targetVal.MapIndex(key); existing.IsValid() && (len(segments) > 1 || a.mergesMapValue(existing, sourceVal)) {
+			elem.Set(existing)
+		}
+		if err := a.setPath(elem, targetKey.newChild(reflect.Map, seg.key), segments[1:], sourceVal); err != nil {
+			return err
+		}
+		targetVal.SetMapIndex(key, elem)
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		index, err := strconv.Atoi(seg.key)
+		if err != nil || index < 0 {
+			return fmt.Errorf("'%s' invalid index '%s'", targetKey, seg.key)
+		}
+		if index >= targetVal.Len() {
+			if targetVal.Kind() == reflect.Array {
+				return fmt.Errorf("'%s' index %d out of range", targetKey, index)
+			}
+			grown := reflect.MakeSlice(targetVal.Type(), index+1, index+1)
+			reflect.Copy(grown, targetVal)
+			targetVal.Set(grown)
+		}
+		return a.setPath(targetVal.Index(index), targetKey.newChild(reflect.Slice, seg.key), segments[1:], sourceVal)
+
+	case reflect.Struct:
+		field, ok := a.structField(targetVal, seg.key, true)
+		if !ok || !field.CanSet() {
+			return fmt.Errorf("'%s' has no field '%s'", targetKey, seg.key)
+		}
+		return a.setPath(field, targetKey.newChild(reflect.Struct, seg.key), segments[1:], sourceVal)
+
+	default:
+		return fmt.Errorf("cannot set '%s' on value of type '%s'", seg, targetVal.Type())
+	}
+}
+
+// structField returns the field of the struct val named key, by its key or
+// its Go name.
+func (a *assigner) structField(val reflect.Value, key string, allocate bool) (reflect.Value, bool) {
+	for _, field := range a.flattenStruct(val, allocate) {
+		if field.actualName == key || field.displayName == key {
+			return field.fieldVal, true
+		}
+	}
+	return reflect.Value{}, false
+}
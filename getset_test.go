@@ -0,0 +1,118 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetPath(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		Name string `json:"name"`
+	}
+	type Root struct {
+		Items []*Item        `json:"items"`
+		Attrs map[int]string `json:"attrs"`
+		Extra map[string]any `json:"extra"`
+	}
+
+	root := Root{
+		Items: []*Item{{Name: "a"}, {Name: "b"}},
+		Attrs: map[int]string{2: "two"},
+		Extra: map[string]any{"list": []any{1, map[string]any{"x": true}}},
+	}
+
+	tests := []struct {
+		path     string
+		expected any
+	}{
+		{"items[1].name", "b"},
+		{"Items[0].Name", "a"},
+		{"attrs.2", "two"},
+		{"extra.list[1].x", true},
+	}
+	for _, test := range tests {
+		value, err := GetPath(&root, test.path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.path, err)
+		}
+		if !reflect.DeepEqual(value, test.expected) {
+			t.Fatalf("%s: expected %#v, got %#v", test.path, test.expected, value)
+		}
+	}
+
+	for _, path := range []string{"items[5].name", "missing", "attrs.x"} {
+		if _, err := GetPath(root, path); err == nil {
+			t.Fatalf("%s: expected an error", path)
+		}
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		Count int `json:"count"`
+	}
+	type Root struct {
+		Items []Item          `json:"items"`
+		Ptr   *Item           `json:"ptr"`
+		Attrs map[string]Item `json:"attrs"`
+		Extra any             `json:"extra"`
+		Fixed [1]int          `json:"fixed"`
+	}
+
+	var root Root
+	sets := []struct {
+		path  string
+		value any
+	}{
+		{"items[2].count", "3"},
+		{"ptr.count", 4},
+		{"attrs.a.count", 5.0},
+		{"extra.list[1]", "x"},
+		{"fixed[0]", 6},
+	}
+	for _, set := range sets {
+		if err := SetPath(&root, set.path, set.value, func(c *AssignConfig) {
+			c.WeaklyTypedInput = true
+		}); err != nil {
+			t.Fatalf("%s: unexpected error: %s", set.path, err)
+		}
+	}
+
+	expected := Root{
+		Items: []Item{{}, {}, {Count: 3}},
+		Ptr:   &Item{Count: 4},
+		Attrs: map[string]Item{"a": {Count: 5}},
+		Extra: map[string]any{"list": []any{nil, "x"}},
+		Fixed: [1]int{6},
+	}
+	if !reflect.DeepEqual(root, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, root)
+	}
+
+	if err := SetPath(&root, "fixed[1]", 1); err == nil {
+		t.Fatalf("expected an out of range error")
+	}
+	if err := SetPath(&root, "ptr.missing", 1); err == nil {
+		t.Fatalf("expected a missing field error")
+	}
+	if err := SetPath(root, "ptr.count", 1); err == nil {
+		t.Fatalf("expected a non pointer error")
+	}
+
+	// Values held by a map[string]any are replaced, whatever their type
+	generic := map[string]any{"a": 1, "b": map[string]any{"c": true, "d": 2}}
+	if err := SetPath(&generic, "a", "str"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := SetPath(&generic, "b.c", []any{"x"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expectedGeneric := map[string]any{"a": "str", "b": map[string]any{"c": []any{"x"}, "d": 2}}
+	if !reflect.DeepEqual(generic, expectedGeneric) {
+		t.Fatalf("expected %+v, got %+v", expectedGeneric, generic)
+	}
+}
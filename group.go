@@ -0,0 +1,100 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// groupKey splits the dotted key name of a struct field assigned to a map
+// of type mapType into the keys of its groups and its own key, see
+// GroupKeys. It returns no groups when the key isn't grouped.
+func (a *assigner) groupKey(mapType reflect.Type, name string) ([]string, string) {
+	if !a.config.GroupKeys || !strings.Contains(name, ".") ||
+		mapType.Key().Kind() != reflect.String || mapType.Elem().Kind() != reflect.Interface || mapType.Elem().NumMethod() != 0 {
+		return nil, name
+	}
+
+	parts := strings.Split(name, ".")
+	for _, part := range parts {
+		if part == "" {
+			return nil, name
+		}
+	}
+	return parts[:len(parts)-1], parts[len(parts)-1]
+}
+
+// groupedKey returns the path of the key name nested in groups below
+// targetKey.
+func groupedKey(targetKey metaKey, groups []string, name string) metaKey {
+	for _, group := range groups {
+		targetKey = targetKey.newChild(reflect.Map, group)
+	}
+	return targetKey.newChild(reflect.Map, name)
+}
+
+// groupMap returns the map nested in targetVal under groups, creating the
+// missing ones as map[string]any. Groups colliding with a value that isn't
+// a map[string]any are an error.
+func (a *assigner) groupMap(targetVal reflect.Value, targetKey metaKey, groups []string) (reflect.Value, error) {
+	current := targetVal
+	for _, group := range groups {
+		targetKey = targetKey.newChild(reflect.Map, group)
+
+		keyVal := reflect.ValueOf(group).Convert(current.Type().Key())
+		existing := current.MapIndex(keyVal)
+		if existing.IsValid() && existing.Kind() == reflect.Interface {
+			existing = existing.Elem()
+		}
+
+		switch {
+		case existing.IsValid() && existing.Type() == anyMapType && !existing.IsNil():
+			current = existing
+		case !existing.IsValid() || isNilSource(existing):
+			child := reflect.MakeMap(anyMapType)
+			current.SetMapIndex(keyVal, child)
+			a.addMetaKey(targetKey)
+			current = child
+		default:
+			return targetVal, fmt.Errorf("'%s' cannot group keys in a value of type '%s'", targetKey.String(), existing.Type())
+		}
+	}
+	return current, nil
+}
+
+// mapFields returns the fields of a struct assigned to a map of type
+// mapType, renamed by FieldNames. When keys are grouped the fields are
+// sorted by key, and keys that are also the group of other keys are an
+// error, so the result doesn't depend on the order of the fields.
+func (a *assigner) mapFields(fields map[string]fieldInfo, mapType reflect.Type, sourceKey metaKey) ([]fieldInfo, error) {
+	result := make([]fieldInfo, 0, len(fields))
+	for _, field := range fields {
+		a.renameField(&field, sourceKey)
+		result = append(result, field)
+	}
+
+	if !a.config.GroupKeys {
+		return result, nil
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].actualName < result[j].actualName
+	})
+
+	names := make(map[string]string, len(result))
+	for _, field := range result {
+		names[field.actualName] = field.displayName
+	}
+	for _, field := range result {
+		groups, _ := a.groupKey(mapType, field.actualName)
+		for i := range groups {
+			group := strings.Join(groups[:i+1], ".")
+			if displayName, ok := names[group]; ok {
+				return nil, fmt.Errorf("'%s' key '%s' of field '%s' conflicts with the group of key '%s' of field '%s'",
+					sourceKey.String(), group, displayName, field.actualName, field.displayName)
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package object
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAssign_GroupKeys(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Name   string `object:"name"`
+		DBHost string `object:"db.host"`
+		DBPort int    `object:"db.port"`
+		DBUser string `object:"db.auth.user,omitempty"`
+		Debug  bool   `object:"log.debug"`
+	}
+
+	source := Config{Name: "app", DBHost: "localhost", DBPort: 5432, Debug: true}
+
+	var out map[string]any
+	meta := &Metadata{}
+	if err := Assign(&out, source, func(c *AssignConfig) {
+		c.TagName = "object"
+		c.GroupKeys = true
+		c.Metadata = meta
+	}); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	want := map[string]any{
+		"name": "app",
+		"db":   map[string]any{"host": "localhost", "port": 5432},
+		"log":  map[string]any{"debug": true},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("Assign() = %#v, want %#v", out, want)
+	}
+	if !slicesContain(meta.Keys, "db[host]") {
+		t.Fatalf("Metadata.Keys = %v, want db[host]", meta.Keys)
+	}
+
+	// Without GroupKeys dotted keys are kept as is.
+	out = nil
+	if err := Assign(&out, source, func(c *AssignConfig) { c.TagName = "object" }); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if out["db.host"] != "localhost" {
+		t.Fatalf("Assign() = %#v, want flat keys", out)
+	}
+
+	// Groups merge into existing maps and conflict with other values.
+	out = map[string]any{"db": map[string]any{"name": "main"}}
+	group := func(c *AssignConfig) {
+		c.TagName = "object"
+		c.GroupKeys = true
+	}
+	if err := Assign(&out, source, group); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if db, _ := out["db"].(map[string]any); db["name"] != "main" || db["host"] != "localhost" {
+		t.Fatalf("Assign() db = %#v", out["db"])
+	}
+
+	out = map[string]any{"log": "stdout"}
+	if err := Assign(&out, source, group); err == nil {
+		t.Fatalf("Assign() expected error grouping into a string")
+	}
+}
+
+func TestAssign_GroupKeysConflict(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		DB     string `json:"db"`
+		DBHost string `json:"db.host"`
+		Name   string `json:"name"`
+	}
+
+	group := func(c *AssignConfig) { c.GroupKeys = true }
+	for i := 0; i < 20; i++ {
+		var out map[string]any
+		err := Assign(&out, Config{DB: "main", DBHost: "localhost"}, group)
+		if err == nil || !strings.Contains(err.Error(), "conflicts with the group") {
+			t.Fatalf("Assign() error = %v, want conflict", err)
+		}
+	}
+}
+
+func slicesContain(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
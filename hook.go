@@ -0,0 +1,134 @@
+package object
+
+import "reflect"
+
+// HookFunc converts a source value before it is assigned to a target of
+// type to. from is the type of data, the returned value is assigned in its
+// place. Hooks usually return data unchanged for the types they don't
+// handle.
+type HookFunc func(from reflect.Type, to reflect.Type, data any) (any, error)
+
+// ComposeHookFunc returns a HookFunc that calls hooks in order, each one
+// receiving the value returned by the previous one. It stops at the first
+// error, or when a hook returns nil.
+func ComposeHookFunc(hooks ...HookFunc) HookFunc {
+	return func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		var err error
+		for _, hook := range hooks {
+			data, err = hook(from, to, data)
+			if err != nil || data == nil {
+				return data, err
+			}
+			from = reflect.TypeOf(data)
+		}
+		return data, nil
+	}
+}
+
+// applyHook runs the configured hook on sourceVal.
+func (a *assigner) applyHook(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value) (reflect.Value, error) {
+	result, err := a.config.Hook(sourceVal.Type(), targetVal.Type(), sourceVal.Interface())
+	if err != nil {
+		return sourceVal, hookError(targetKey.String(), err)
+	}
+	return reflect.ValueOf(result), nil
+}
+
+// ValueHookFunc is a HookFunc receiving the source value from and the
+// target value to instead of their types and an interface, which avoids
+// allocations in hot hooks and lets hooks inspect the target, e.g. its
+// current value or whether it is addressable. The target must not be
+// modified. The returned value is assigned in place of from, an invalid
+// value is assigned like a nil source.
+type ValueHookFunc func(from reflect.Value, to reflect.Value) (reflect.Value, error)
+
+// ComposeValueHookFunc returns a ValueHookFunc that calls hooks in order,
+// see ComposeHookFunc.
+func ComposeValueHookFunc(hooks ...ValueHookFunc) ValueHookFunc {
+	return func(from reflect.Value, to reflect.Value) (reflect.Value, error) {
+		var err error
+		for _, hook := range hooks {
+			from, err = hook(from, to)
+			if err != nil || !from.IsValid() {
+				return from, err
+			}
+		}
+		return from, nil
+	}
+}
+
+// applyValueHook runs the configured value hook on sourceVal, see
+// applyHook.
+func (a *assigner) applyValueHook(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value) (reflect.Value, error) {
+	result, err := a.config.ValueHook(sourceVal, targetVal)
+	if err != nil {
+		return sourceVal, hookError(targetKey.String(), err)
+	}
+	if result.IsValid() && result.Kind() == reflect.Interface {
+		result = result.Elem()
+	}
+	return result, nil
+}
+
+// HookContext describes the value passed to a ContextHookFunc.
+type HookContext struct {
+	// Path is the full path of the target, such as "Items[2].Name".
+	Path string
+
+	// Parent is the type of the struct holding the target field, nil when
+	// the target is not a struct field. Fields of embedded structs have
+	// the outer struct as parent.
+	Parent reflect.Type
+
+	// Tag is the tag of the target field, empty when the target is not a
+	// struct field.
+	Tag reflect.StructTag
+}
+
+// ContextHookFunc is a HookFunc that also receives the HookContext of the
+// value, so conversions can depend on where the value is assigned, e.g.
+// parsing strings into time.Time with the layout of a "layout" tag.
+type ContextHookFunc func(ctx HookContext, from reflect.Type, to reflect.Type, data any) (any, error)
+
+// applyContextHook runs the configured context hook on sourceVal, see
+// applyHook.
+func (a *assigner) applyContextHook(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, field *fieldInfo) (reflect.Value, error) {
+	ctx := HookContext{Path: targetKey.String()}
+	if field != nil {
+		ctx.Parent = field.parent
+		ctx.Tag = field.field.Tag
+	}
+
+	result, err := a.config.ContextHook(ctx, sourceVal.Type(), targetVal.Type(), sourceVal.Interface())
+	if err != nil {
+		return sourceVal, hookError(ctx.Path, err)
+	}
+	return reflect.ValueOf(result), nil
+}
+
+// applyCollectionHook runs the configured collection hook on sourceVal.
+func (a *assigner) applyCollectionHook(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value) (reflect.Value, error) {
+	result, err := a.config.CollectionHook(sourceVal.Type(), targetVal.Type(), sourceVal.Interface())
+	if err != nil {
+		return sourceVal, hookError(targetKey.String(), err)
+	}
+	return reflect.ValueOf(result), nil
+}
+
+// isCollection reports whether val is a non nil slice, array or map.
+func isCollection(val reflect.Value) bool {
+	return val.IsValid() && isCollectionKind(val.Kind()) && !isNilSource(val)
+}
+
+func isCollectionKind(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+}
+
+// hookError attaches a hook error to path unless the hook already returned
+// a *FieldError.
+func hookError(path string, err error) error {
+	if _, ok := err.(*FieldError); ok {
+		return err
+	}
+	return NewFieldError(path, err)
+}
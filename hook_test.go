@@ -0,0 +1,303 @@
+package object
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssign_Hook(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Timeout time.Duration `json:"timeout"`
+		Name    string        `json:"name"`
+	}
+
+	hook := func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if from.Kind() == reflect.String && to == reflect.TypeOf(time.Duration(0)) {
+			return time.ParseDuration(data.(string))
+		}
+		return data, nil
+	}
+
+	input := map[string]any{
+		"timeout": "1m30s",
+		"name":    "foo",
+	}
+
+	var result Target
+	if err := Assign(&result, input, func(c *AssignConfig) {
+		c.Hook = hook
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Target{Timeout: 90 * time.Second, Name: "foo"}
+	if result != expected {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestAssign_HookError(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		Timeout time.Duration `json:"timeout"`
+	}
+
+	type Target struct {
+		Items []Item `json:"items"`
+	}
+
+	errInvalid := errors.New("invalid duration")
+	hook := func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if to == reflect.TypeOf(time.Duration(0)) {
+			return nil, errInvalid
+		}
+		return data, nil
+	}
+
+	input := map[string]any{
+		"items": []any{
+			map[string]any{"timeout": "bad"},
+		},
+	}
+
+	var result Target
+	err := Assign(&result, input, func(c *AssignConfig) {
+		c.Hook = hook
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "'Items[0].Timeout' invalid duration") {
+		t.Fatalf("bad error: %s", err)
+	}
+
+	// A FieldError returned by the hook keeps its own path
+	hook = func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if to == reflect.TypeOf(time.Duration(0)) {
+			return nil, NewFieldError("custom.path", errInvalid)
+		}
+		return data, nil
+	}
+
+	var direct time.Duration
+	err = Assign(&direct, "bad", func(c *AssignConfig) {
+		c.Hook = hook
+	})
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("expected FieldError, got %T: %v", err, err)
+	}
+	if fieldErr.Path != "custom.path" || !errors.Is(err, errInvalid) {
+		t.Fatalf("bad error: %#v", fieldErr)
+	}
+}
+
+func TestAssign_ContextHook(t *testing.T) {
+	t.Parallel()
+
+	type Event struct {
+		Day  time.Time `json:"day" layout:"2006-01-02"`
+		Note string    `json:"note"`
+	}
+
+	type Target struct {
+		Event  Event             `json:"event"`
+		Labels map[string]string `json:"labels"`
+	}
+
+	var contexts []HookContext
+	hook := func(ctx HookContext, from reflect.Type, to reflect.Type, data any) (any, error) {
+		contexts = append(contexts, ctx)
+		if layout := ctx.Tag.Get("layout"); layout != "" && from.Kind() == reflect.String {
+			return time.Parse(layout, data.(string))
+		}
+		return data, nil
+	}
+
+	input := map[string]any{
+		"event":  map[string]any{"day": "2024-03-01", "note": "2024-03-01"},
+		"labels": map[string]any{"a": "b"},
+	}
+
+	var result Target
+	if err := Assign(&result, input, func(c *AssignConfig) {
+		c.ContextHook = hook
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !result.Event.Day.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) || result.Event.Note != "2024-03-01" {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	byPath := map[string]HookContext{}
+	for _, ctx := range contexts {
+		byPath[ctx.Path] = ctx
+	}
+
+	if ctx := byPath["Event.Day"]; ctx.Parent != reflect.TypeOf(Event{}) || ctx.Tag.Get("layout") == "" {
+		t.Fatalf("bad field context: %#v", ctx)
+	}
+	if ctx, ok := byPath["Labels[a]"]; !ok || ctx.Parent != nil || ctx.Tag != "" {
+		t.Fatalf("bad map context: %#v", byPath)
+	}
+}
+
+func TestComposeHookFunc(t *testing.T) {
+	t.Parallel()
+
+	trim := func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if s, ok := data.(string); ok {
+			return strings.TrimSpace(s), nil
+		}
+		return data, nil
+	}
+	duration := func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if from.Kind() == reflect.String && to == reflect.TypeOf(time.Duration(0)) {
+			return time.ParseDuration(data.(string))
+		}
+		return data, nil
+	}
+
+	calls := 0
+	count := func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		calls++
+		return data, nil
+	}
+
+	var result time.Duration
+	if err := Assign(&result, " 2s ", func(c *AssignConfig) {
+		c.Hook = ComposeHookFunc(trim, duration, count)
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != 2*time.Second || calls != 1 {
+		t.Fatalf("bad: %v, %d calls", result, calls)
+	}
+
+	// The chain stops at the first error
+	calls = 0
+	err := Assign(&result, "bad", func(c *AssignConfig) {
+		c.Hook = ComposeHookFunc(duration, count)
+	})
+	if err == nil || calls != 0 {
+		t.Fatalf("expected error without further calls, got %v, %d calls", err, calls)
+	}
+}
+
+func TestAssign_CollectionHook(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Ports  []int          `json:"ports"`
+		Labels map[string]int `json:"labels"`
+		Name   string         `json:"name"`
+	}
+
+	var calls []string
+	hook := func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		calls = append(calls, to.String())
+		switch v := data.(type) {
+		case []any:
+			// Drop disabled entries
+			filtered := make([]any, 0, len(v))
+			for _, elem := range v {
+				if elem != "off" {
+					filtered = append(filtered, elem)
+				}
+			}
+			return filtered, nil
+		case map[string]any:
+			// Re-key to lower case
+			rekeyed := make(map[string]any, len(v))
+			for k, elem := range v {
+				rekeyed[strings.ToLower(k)] = elem
+			}
+			return rekeyed, nil
+		}
+		return data, nil
+	}
+
+	input := map[string]any{
+		"ports":  []any{80, "off", 443},
+		"labels": map[string]any{"A": 1, "B": 2},
+		"name":   "x",
+	}
+
+	var result Target
+	if err := Assign(&result, input, func(c *AssignConfig) {
+		c.CollectionHook = hook
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Target{
+		Ports:  []int{80, 443},
+		Labels: map[string]int{"a": 1, "b": 2},
+		Name:   "x",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	// Once per collection, never for scalars nor the struct itself
+	sort.Strings(calls)
+	if !reflect.DeepEqual(calls, []string{"[]int", "map[string]int"}) {
+		t.Fatalf("bad calls: %#v", calls)
+	}
+}
+
+func TestAssign_ValueHook(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Timeout time.Duration `json:"timeout"`
+		Name    string        `json:"name"`
+		Kept    string        `json:"kept"`
+	}
+
+	durationType := reflect.TypeOf(time.Duration(0))
+	parse := func(from reflect.Value, to reflect.Value) (reflect.Value, error) {
+		if from.Kind() == reflect.String && to.Type() == durationType {
+			d, err := time.ParseDuration(from.String())
+			return reflect.ValueOf(d), err
+		}
+		return from, nil
+	}
+	// String targets that are already set keep their value
+	keep := func(from reflect.Value, to reflect.Value) (reflect.Value, error) {
+		if to.Kind() == reflect.String && to.String() != "" {
+			return to, nil
+		}
+		return from, nil
+	}
+
+	result := Target{Kept: "old"}
+	err := Assign(&result, map[string]any{"timeout": "2s", "name": "n", "kept": "new"}, func(c *AssignConfig) {
+		c.ValueHook = ComposeValueHookFunc(parse, keep)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Target{Timeout: 2 * time.Second, Name: "n", Kept: "old"}
+	if result != expected {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+
+	err = Assign(&result, map[string]any{"timeout": "bad"}, func(c *AssignConfig) {
+		c.ValueHook = parse
+	})
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Path != "Timeout" {
+		t.Fatalf("expected a field error for 'Timeout', got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package object
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// KeyStringPolicy selects how non string map keys, such as the keys of the
+// map[any]any values produced by YAML decoders, are converted to string
+// keys.
+type KeyStringPolicy int
+
+const (
+	// KeyStringWeak converts keys like WeaklyTypedInput converts values,
+	// e.g. true becomes "1".
+	KeyStringWeak KeyStringPolicy = iota
+
+	// KeyStringFormatV formats keys with fmt's %v verb, e.g. 1e6 becomes
+	// "1e+06" and true becomes "true".
+	KeyStringFormatV
+
+	// KeyStringJSON formats keys as JSON would: integers in base 10,
+	// floats without exponent, booleans as "true" or "false" and
+	// encoding.TextMarshaler keys as their text. Other keys are an error.
+	KeyStringJSON
+
+	// KeyStringError rejects non string keys.
+	KeyStringError
+)
+
+// assignStringKey converts a non string key sourceVal into the string key
+// keyVal according to the KeyStringification policy. It reports false when
+// the policy doesn't apply.
+func (a *assigner) assignStringKey(keyVal reflect.Value, sourceVal reflect.Value) (bool, error) {
+	if a.config.KeyStringification == KeyStringWeak || keyVal.Kind() != reflect.String {
+		return false, nil
+	}
+	if sourceVal.IsValid() && sourceVal.Kind() == reflect.String {
+		return false, nil
+	}
+
+	var str string
+	switch a.config.KeyStringification {
+	case KeyStringFormatV:
+		str = mapKeyString(sourceVal)
+	case KeyStringJSON:
+		s, ok := jsonKeyString(sourceVal)
+		if !ok {
+			return true, unsupportedKeyError(sourceVal)
+		}
+		str = s
+	default:
+		return true, unsupportedKeyError(sourceVal)
+	}
+
+	keyVal.SetString(str)
+	return true, nil
+}
+
+// jsonKeyString formats key as JSON would.
+func jsonKeyString(key reflect.Value) (string, bool) {
+	if !key.IsValid() {
+		return "", false
+	}
+
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		if key.Kind() == reflect.Ptr && key.IsNil() {
+			return "", false
+		}
+		text, err := marshaler.MarshalText()
+		return string(text), err == nil
+	}
+
+	if isBool(key.Kind()) {
+		return strconv.FormatBool(key.Bool()), true
+	}
+	return formatNumber(key)
+}
+
+func unsupportedKeyError(key reflect.Value) error {
+	if !key.IsValid() {
+		return fmt.Errorf("cannot use nil key as string")
+	}
+	return fmt.Errorf("cannot use key '%v' of type '%s' as string", key.Interface(), key.Type())
+}
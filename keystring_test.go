@@ -0,0 +1,66 @@
+package object
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type keyStringID int
+
+func (id keyStringID) MarshalText() ([]byte, error) {
+	return []byte("id-" + strconv.Itoa(int(id))), nil
+}
+
+func TestAssign_KeyStringification(t *testing.T) {
+	t.Parallel()
+
+	source := map[any]any{1e6: "a", true: "b", 1.5: "c", 7: "d"}
+
+	tests := []struct {
+		policy   KeyStringPolicy
+		expected map[string]any
+	}{
+		{KeyStringWeak, map[string]any{"1000000": "a", "1": "b", "1.5": "c", "7": "d"}},
+		{KeyStringFormatV, map[string]any{"1e+06": "a", "true": "b", "1.5": "c", "7": "d"}},
+		{KeyStringJSON, map[string]any{"1000000": "a", "true": "b", "1.5": "c", "7": "d"}},
+	}
+
+	for _, test := range tests {
+		var result map[string]any
+		err := Assign(&result, source, func(c *AssignConfig) {
+			c.KeyStringification = test.policy
+		})
+		if err != nil {
+			t.Fatalf("policy %d: unexpected error: %s", test.policy, err)
+		}
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Fatalf("policy %d: expected %v, got %v", test.policy, test.expected, result)
+		}
+	}
+
+	// TextMarshaler keys are JSON compatible, other structs aren't.
+	var result map[string]int
+	err := Assign(&result, map[any]int{keyStringID(3): 1}, func(c *AssignConfig) {
+		c.KeyStringification = KeyStringJSON
+	})
+	if err != nil || result["id-3"] != 1 {
+		t.Fatalf("expected the text key, got %v (%v)", result, err)
+	}
+
+	err = Assign(&result, map[any]int{struct{ A int }{1}: 1}, func(c *AssignConfig) {
+		c.KeyStringification = KeyStringJSON
+	})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	// String keys are always accepted.
+	err = Assign(&result, map[any]int{"a": 1, 2: 2}, func(c *AssignConfig) {
+		c.KeyStringification = KeyStringError
+	})
+	e, ok := err.(*Error)
+	if !ok || len(e.Errors) != 1 || result["a"] != 1 {
+		t.Fatalf("expected a single error for the int key, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// KV is a single key/value pair. Assigning a map or a struct to a []KV
+// target produces one pair per entry, which is useful when an ordered
+// representation is needed instead of a map. See AssignConfig.SortKeys.
+type KV struct {
+	Key   string
+	Value any
+}
+
+var kvSliceType = reflect.TypeOf([]KV{})
+
+func (a *assigner) assignKVs(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) error {
+	sourceVal = reflect.Indirect(sourceVal)
+
+	switch sourceVal.Kind() {
+	case reflect.Map:
+		// Entries are collected below
+	case reflect.Struct:
+		entries := map[string]any{}
+		entriesVal := reflect.ValueOf(entries)
+		if err := a.assignMapFromStruct(entriesVal, targetKey, sourceVal, sourceKey); err != nil {
+			return err
+		}
+		sourceVal = entriesVal
+	default:
+		return fmt.Errorf("'%s' expected a map or struct, got '%s'", targetKey.String(), sourceVal.Kind())
+	}
+
+	kvs := make([]KV, 0, sourceVal.Len())
+	iter := sourceVal.MapRange()
+	for iter.Next() {
+		key := mapKeyString(iter.Key())
+
+		if a.shouldSkipKey(targetKey.newChild(reflect.Map, key), sourceKey.newChild(reflect.Map, key)) {
+			continue
+		}
+
+		kvs = append(kvs, KV{
+			Key:   key,
+			Value: iter.Value().Interface(),
+		})
+	}
+
+	if a.config.SortKeys {
+		sort.Slice(kvs, func(i, j int) bool {
+			return kvs[i].Key < kvs[j].Key
+		})
+	}
+
+	targetVal.Set(reflect.ValueOf(kvs))
+	return nil
+}
+
+// Field is a single struct field. Assigning a struct to a []Field target
+// produces one entry per field in declaration order, which some templating
+// and table rendering systems need instead of a map.
+type Field struct {
+	// Name is the key of the field, as it would appear in a map.
+	Name  string
+	Value any
+}
+
+var fieldSliceType = reflect.TypeOf([]Field{})
+
+func (a *assigner) assignFields(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) error {
+	sourceVal = reflect.Indirect(sourceVal)
+	if sourceVal.Kind() != reflect.Struct {
+		return fmt.Errorf("'%s' expected a struct, got '%s'", targetKey.String(), sourceVal.Kind())
+	}
+
+	entries := map[string]any{}
+	if err := a.assignMapFromStruct(reflect.ValueOf(entries), targetKey, sourceVal, sourceKey); err != nil {
+		return err
+	}
+
+	order := make(map[string]int)
+	a.fieldOrder(sourceVal.Type(), order, make(map[reflect.Type]struct{}))
+
+	type orderedField struct {
+		Field
+		order int
+	}
+
+	sourceFields := a.flattenStruct(sourceVal, false)
+	ordered := make([]orderedField, 0, len(entries))
+	for _, srcField := range sourceFields {
+		value, ok := entries[srcField.actualName]
+		if !ok {
+			continue
+		}
+		ordered = append(ordered, orderedField{
+			Field: Field{Name: srcField.actualName, Value: value},
+			order: order[srcField.displayName],
+		})
+	}
+
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].order < ordered[j].order
+	})
+
+	fields := make([]Field, len(ordered))
+	for i, f := range ordered {
+		fields[i] = f.Field
+	}
+
+	targetVal.Set(reflect.ValueOf(fields))
+	return nil
+}
+
+// fieldOrder records the declaration position of every field name of
+// structType, descending depth-first into the embedded and squash tagged
+// structs that flattenStruct squashes.
+func (a *assigner) fieldOrder(structType reflect.Type, order map[string]int, visited map[reflect.Type]struct{}) {
+	if _, exist := visited[structType]; exist {
+		return
+	}
+	visited[structType] = struct{}{}
+
+	tags := a.structTags(structType)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		if opts := tags[i].opts; (field.Anonymous || opts.Squash) && a.squashable(field, opts) {
+			a.fieldOrder(indirectType(field.Type), order, visited)
+			continue
+		}
+
+		if _, exist := order[field.Name]; !exist {
+			order[field.Name] = len(order)
+		}
+	}
+}
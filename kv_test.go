@@ -0,0 +1,135 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssign_KVSortKeys(t *testing.T) {
+	t.Parallel()
+
+	sortKeys := func(c *AssignConfig) {
+		c.SortKeys = true
+	}
+
+	input := map[string]any{
+		"zeta":  1,
+		"alpha": "a",
+		"mid":   true,
+	}
+
+	var result []KV
+	if err := Assign(&result, input, sortKeys); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []KV{
+		{Key: "alpha", Value: "a"},
+		{Key: "mid", Value: true},
+		{Key: "zeta", Value: 1},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, result)
+	}
+
+	type Server struct {
+		Port int
+		Host string
+	}
+
+	var fromStruct []KV
+	if err := Assign(&fromStruct, Server{Port: 80, Host: "localhost"}, sortKeys); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected = []KV{
+		{Key: "host", Value: "localhost"},
+		{Key: "port", Value: 80},
+	}
+	if !reflect.DeepEqual(fromStruct, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, fromStruct)
+	}
+
+	var copied []KV
+	if err := Assign(&copied, expected); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(copied, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, copied)
+	}
+}
+
+func TestAssign_Fields(t *testing.T) {
+	t.Parallel()
+
+	type Base struct {
+		ID      int
+		Created string
+	}
+
+	type Row struct {
+		Name string
+		Base
+		Email string `json:"mail"`
+		Note  string `json:"note,omitempty"`
+	}
+
+	var result []Field
+	err := Assign(&result, Row{
+		Name:  "gopher",
+		Base:  Base{ID: 7, Created: "today"},
+		Email: "gopher@example.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []Field{
+		{Name: "name", Value: "gopher"},
+		{Name: "id", Value: 7},
+		{Name: "created", Value: "today"},
+		{Name: "mail", Value: "gopher@example.com"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, result)
+	}
+
+	if err := Assign(&result, map[string]any{"name": "x"}); err == nil {
+		t.Fatal("expected error for map source")
+	}
+}
+
+func TestAssign_FieldsSquashed(t *testing.T) {
+	t.Parallel()
+
+	type Audit struct {
+		Created string
+		Updated string
+	}
+
+	type Row struct {
+		Name  string
+		Audit Audit `json:",squash"`
+		Email string
+	}
+
+	var result []Field
+	err := Assign(&result, Row{
+		Name:  "gopher",
+		Audit: Audit{Created: "today", Updated: "now"},
+		Email: "gopher@example.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []Field{
+		{Name: "name", Value: "gopher"},
+		{Name: "created", Value: "today"},
+		{Name: "updated", Value: "now"},
+		{Name: "email", Value: "gopher@example.com"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, result)
+	}
+}
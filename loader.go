@@ -0,0 +1,92 @@
+package object
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FileMetadata is the Metadata of a single file loaded by LoadFiles.
+type FileMetadata struct {
+	Path string
+	Metadata
+}
+
+// LoadFiles decodes the files at paths in order and layers them into
+// target, each file overriding the keys set by the previous ones while
+// keeping the others, so defaults can be followed by environment specific
+// overrides. A directory includes the regular files it contains in name
+// order, giving "config.d" semantics, skipping the files whose extension
+// has no registered codec, such as a README. The format of each file is
+// selected by its extension through the registered codecs, and sniffed as
+// in DecodeAuto when the extension of a file named explicitly is unknown.
+//
+// It returns the Metadata of every loaded file, in load order, so unused
+// keys can be reported per file. Loading stops at the first error.
+func LoadFiles(target any, paths ...string) ([]FileMetadata, error) {
+	files, err := expandPaths(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]FileMetadata, 0, len(files))
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return result, err
+		}
+
+		name := path
+		if _, _, ok := lookupCodec(path); !ok {
+			name = detectFormat(data)
+		}
+
+		md := FileMetadata{Path: path}
+		if err := decodeFormat(name, data, target, func(c *AssignConfig) {
+			c.Metadata = &md.Metadata
+		}); err != nil {
+			return result, fmt.Errorf("%s: %w", path, err)
+		}
+
+		result = append(result, md)
+	}
+
+	return result, nil
+}
+
+// expandPaths replaces the directories of paths by the regular files they
+// contain whose extension has a registered codec, sorted by name.
+func expandPaths(paths []string) ([]string, error) {
+	files := make([]string, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			if _, _, ok := lookupCodec(entry.Name()); ok && entry.Type().IsRegular() {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			files = append(files, filepath.Join(path, name))
+		}
+	}
+
+	return files, nil
+}
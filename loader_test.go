@@ -0,0 +1,94 @@
+package object
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestLoadFiles(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Name   string
+		Port   int
+		Labels map[string]string
+	}
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.json")
+	writeFile(t, base, `{"name": "svc", "port": 80, "labels": {"env": "dev", "team": "a"}}`)
+	writeFile(t, filepath.Join(dir, "config.d", "20-port.json"), `{"port": 8080}`)
+	writeFile(t, filepath.Join(dir, "config.d", "10-labels.json"), `{"labels": {"env": "prod"}, "unknown": 1}`)
+	writeFile(t, filepath.Join(dir, "config.d", "README.md"), "# Overrides")
+	override := filepath.Join(dir, "override.conf")
+	writeFile(t, override, `{"name": "api"}`)
+
+	// Directories skip the files without a codec, files named explicitly
+	// are sniffed
+	var config Config
+	mds, err := LoadFiles(&config, base, filepath.Join(dir, "config.d"), override)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Config{
+		Name:   "api",
+		Port:   8080,
+		Labels: map[string]string{"env": "prod", "team": "a"},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, config)
+	}
+
+	if len(mds) != 4 {
+		t.Fatalf("expected metadata for four files, got %d", len(mds))
+	}
+	if !strings.HasSuffix(mds[1].Path, "10-labels.json") || !strings.HasSuffix(mds[2].Path, "20-port.json") {
+		t.Fatalf("files must be loaded in name order: %#v", mds)
+	}
+	if !reflect.DeepEqual(mds[1].Unused, []string{"unknown"}) {
+		t.Fatalf("expected unused keys per file, got %#v", mds[1].Unused)
+	}
+	if len(mds[0].Unused) != 0 {
+		t.Fatalf("bad: %#v", mds[0].Unused)
+	}
+}
+
+func TestLoadFiles_Errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	bad := filepath.Join(dir, "bad.json")
+	writeFile(t, good, `{"port": 1}`)
+	writeFile(t, bad, `{"port": "x"}`)
+
+	var config struct {
+		Port int
+	}
+
+	if _, err := LoadFiles(&config, filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatalf("expected an error for a missing file")
+	}
+
+	mds, err := LoadFiles(&config, good, bad)
+	if err == nil || !strings.Contains(err.Error(), bad) {
+		t.Fatalf("expected an error naming %s, got %v", bad, err)
+	}
+	if len(mds) != 1 || config.Port != 1 {
+		t.Fatalf("bad: %#v, %#v", mds, config)
+	}
+}
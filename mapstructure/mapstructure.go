@@ -0,0 +1,406 @@
+// Package mapstructure exposes the API of github.com/mitchellh/mapstructure
+// on top of object.Assign, so that code written for mapstructure can
+// migrate by changing its import path. New code should use object.Assign.
+//
+// Fields are matched by their mapstructure tag, or by their name ignoring
+// case, and embedded structs are only squashed when Squash is set or they
+// are tagged ",squash", like mapstructure does. The decode hook helpers of
+// mapstructure are not provided, object.HookFunc values are accepted as
+// DecodeHookFuncType.
+package mapstructure
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/epkgs/object"
+)
+
+// DecodeHookFunc is the callback function that can be used for data
+// transformations. It must be a DecodeHookFuncType, a DecodeHookFuncKind,
+// a DecodeHookFuncValue or a function with the signature of one of them.
+type DecodeHookFunc any
+
+// DecodeHookFuncType is a DecodeHookFunc which has complete information
+// about the source and target types.
+type DecodeHookFuncType func(reflect.Type, reflect.Type, any) (any, error)
+
+// DecodeHookFuncKind is a DecodeHookFunc which knows only the Kinds of the
+// source and target types.
+type DecodeHookFuncKind func(reflect.Kind, reflect.Kind, any) (any, error)
+
+// DecodeHookFuncValue is a DecodeHookFunc which has complete access to
+// both the source and target values.
+type DecodeHookFuncValue func(from reflect.Value, to reflect.Value) (any, error)
+
+// Error holds the errors of a decode, see object.Error.
+type Error = object.Error
+
+// DecoderConfig is the configuration that is used to create a new decoder
+// and allows customization of various aspects of decoding.
+type DecoderConfig struct {
+	// DecodeHook, if set, will be called before any decoding and any
+	// type conversion (if WeaklyTypedInput is on).
+	DecodeHook DecodeHookFunc
+
+	// If ErrorUnused is true, then it is an error for there to exist
+	// keys in the original map that were unused in the decoding process
+	// (extra keys).
+	ErrorUnused bool
+
+	// If ErrorUnset is true, then it is an error for there to exist
+	// fields in the result that were not set in the decoding process
+	// (extra fields).
+	ErrorUnset bool
+
+	// ZeroFields, if set to true, will zero fields before writing them.
+	// For example, a map will be emptied before decoded values are put in
+	// it. If this is false, a map will be merged.
+	ZeroFields bool
+
+	// If WeaklyTypedInput is true, the decoder will make the "weak"
+	// conversions of object.AssignConfig.WeaklyTypedInput.
+	WeaklyTypedInput bool
+
+	// Squash will squash embedded structs.
+	Squash bool
+
+	// Metadata is the struct that will contain extra metadata about
+	// the decoding. If this is nil, then no metadata will be tracked.
+	Metadata *Metadata
+
+	// Result is a pointer to the struct that will contain the decoded
+	// value.
+	Result any
+
+	// The tag name that mapstructure reads for field names. This
+	// defaults to "mapstructure".
+	TagName string
+
+	// IgnoreUntaggedFields ignores all struct fields without explicit
+	// TagName, comparable to `mapstructure:"-"` as default behaviour.
+	IgnoreUntaggedFields bool
+
+	// MatchName is the function used to match the map key to the struct
+	// field name or tag. Defaults to strings.EqualFold.
+	MatchName func(mapKey, fieldName string) bool
+}
+
+// Metadata contains information about decoding a structure that is
+// tedious or difficult to get otherwise.
+type Metadata struct {
+	// Keys are the keys of the structure which were successfully decoded
+	Keys []string
+
+	// Unused is a slice of keys that were found in the raw value but
+	// weren't decoded since there was no matching field in the result.
+	Unused []string
+
+	// Unset is a slice of field names that were found in the result
+	// interface but weren't set in the decoding process since there was
+	// no matching value in the input.
+	Unset []string
+}
+
+// A Decoder takes a raw interface value and turns it into structured
+// data, keeping track of rich error information along the way in case
+// anything goes wrong.
+type Decoder struct {
+	config    *DecoderConfig
+	hook      object.HookFunc
+	valueHook object.ValueHookFunc
+}
+
+// Decode decodes the given raw interface to the target pointer specified
+// by the configuration.
+func (d *Decoder) Decode(input any) error {
+	var md *object.Metadata
+	if d.config.Metadata != nil || d.config.ErrorUnset {
+		md = &object.Metadata{}
+	}
+
+	err := object.Assign(d.config.Result, input, func(c *object.AssignConfig) {
+		c.TagName = d.config.TagName
+		c.Converter = fieldName
+		c.MatchName = d.config.MatchName
+		c.WeaklyTypedInput = d.config.WeaklyTypedInput
+		c.ErrorUnused = d.config.ErrorUnused
+		c.ZeroFields = d.config.ZeroFields
+		c.IgnoreUntaggedFields = d.config.IgnoreUntaggedFields
+		c.Hook = d.hook
+		c.ValueHook = d.valueHook
+		c.Metadata = md
+		c.DeepInterfaceMaps = true
+		if !d.config.Squash {
+			c.Squash = object.SquashTagged
+		}
+	})
+
+	if md != nil {
+		d.tagPaths(md)
+	}
+	if d.config.Metadata != nil {
+		d.config.Metadata.Keys = append(d.config.Metadata.Keys, md.Keys...)
+		d.config.Metadata.Unused = append(d.config.Metadata.Unused, md.Unused...)
+		d.config.Metadata.Unset = append(d.config.Metadata.Unset, md.Unset...)
+	}
+
+	if d.config.ErrorUnset {
+		err = appendUnsetErrors(err, md)
+	}
+	return err
+}
+
+// NewDecoder returns a new decoder for the given configuration. Once
+// a decoder has been returned, the same configuration must not be used
+// again.
+func NewDecoder(config *DecoderConfig) (*Decoder, error) {
+	val := reflect.ValueOf(config.Result)
+	if val.Kind() != reflect.Ptr {
+		return nil, errors.New("result must be a pointer")
+	}
+
+	val = val.Elem()
+	if !val.CanAddr() {
+		return nil, errors.New("result must be addressable (a pointer)")
+	}
+
+	if config.Metadata != nil {
+		if config.Metadata.Keys == nil {
+			config.Metadata.Keys = make([]string, 0)
+		}
+		if config.Metadata.Unused == nil {
+			config.Metadata.Unused = make([]string, 0)
+		}
+		if config.Metadata.Unset == nil {
+			config.Metadata.Unset = make([]string, 0)
+		}
+	}
+
+	if config.TagName == "" {
+		config.TagName = "mapstructure"
+	}
+
+	if config.MatchName == nil {
+		config.MatchName = strings.EqualFold
+	}
+
+	decoder := &Decoder{config: config}
+	if err := decoder.setHook(config.DecodeHook); err != nil {
+		return nil, err
+	}
+	return decoder, nil
+}
+
+// Decode takes an input structure and uses reflection to translate it to
+// the output structure. output must be a pointer to a map or struct.
+func Decode(input any, output any) error {
+	return decode(input, &DecoderConfig{Result: output})
+}
+
+// WeakDecode is the same as Decode but is shorthand to enable
+// WeaklyTypedInput. See DecoderConfig for more info.
+func WeakDecode(input, output any) error {
+	return decode(input, &DecoderConfig{Result: output, WeaklyTypedInput: true})
+}
+
+// DecodeMetadata is the same as Decode, but is shorthand to enable
+// metadata collection. See DecoderConfig for more info.
+func DecodeMetadata(input any, output any, metadata *Metadata) error {
+	return decode(input, &DecoderConfig{Result: output, Metadata: metadata})
+}
+
+// WeakDecodeMetadata is the same as Decode, but is shorthand to enable
+// both WeaklyTypedInput and metadata collection. See DecoderConfig for
+// more info.
+func WeakDecodeMetadata(input any, output any, metadata *Metadata) error {
+	return decode(input, &DecoderConfig{Result: output, Metadata: metadata, WeaklyTypedInput: true})
+}
+
+func decode(input any, config *DecoderConfig) error {
+	decoder, err := NewDecoder(config)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(input)
+}
+
+// tagPaths rewrites the Keys, Unset and UnsetReasons paths of md, which
+// name struct fields by their Go name, with the tag names of the fields,
+// like mapstructure reports them.
+func (d *Decoder) tagPaths(md *object.Metadata) {
+	resultType := reflect.TypeOf(d.config.Result).Elem()
+	for i, path := range md.Keys {
+		md.Keys[i] = tagPath(resultType, d.config.TagName, path)
+	}
+
+	reasons := make(map[string]object.UnsetReason, len(md.UnsetReasons))
+	for i, path := range md.Unset {
+		md.Unset[i] = tagPath(resultType, d.config.TagName, path)
+		if reason, ok := md.UnsetReasons[path]; ok {
+			reasons[md.Unset[i]] = reason
+		}
+	}
+	md.UnsetReasons = reasons
+}
+
+// tagPath returns path, a path of typ, with its struct fields named by
+// their tagName tag when they have one.
+func tagPath(typ reflect.Type, tagName, path string) string {
+	segments, err := object.SplitPath(path)
+	if err != nil {
+		return path
+	}
+
+	var b strings.Builder
+	for _, seg := range segments {
+		for typ != nil && typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		name := seg.Name
+		switch {
+		case typ == nil:
+		case typ.Kind() == reflect.Struct && seg.Kind == object.PathField:
+			if field, ok := structField(typ, tagName, seg.Name); ok {
+				if tag := tagValue(field, tagName); tag != "" {
+					name = tag
+				}
+				typ = field.Type
+			} else {
+				typ = nil
+			}
+		case typ.Kind() == reflect.Map || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+			typ = typ.Elem()
+		default:
+			typ = nil
+		}
+
+		switch {
+		case seg.Kind != object.PathField:
+			b.WriteString("[" + name + "]")
+		case b.Len() > 0:
+			b.WriteString("." + name)
+		default:
+			b.WriteString(name)
+		}
+	}
+	return b.String()
+}
+
+// structField returns the field of typ with the Go name name, looking
+// into embedded and squash tagged structs.
+func structField(typ reflect.Type, tagName, name string) (reflect.StructField, bool) {
+	if field, ok := typ.FieldByName(name); ok {
+		return field, true
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() != reflect.Struct || !strings.Contains(field.Tag.Get(tagName), ",squash") {
+			continue
+		}
+		if field, ok := structField(fieldType, tagName, name); ok {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// tagValue returns the name of the tagName tag of field, if any.
+func tagValue(field reflect.StructField, tagName string) string {
+	tag := field.Tag.Get(tagName)
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag == "-" {
+		return ""
+	}
+	return tag
+}
+
+// fieldName names untagged fields by their Go name, like mapstructure.
+func fieldName(name string) string {
+	return name
+}
+
+// setHook converts a DecodeHookFunc into the hook of the decoder.
+func (d *Decoder) setHook(hook DecodeHookFunc) error {
+	if hook == nil {
+		return nil
+	}
+
+	hookVal := reflect.ValueOf(hook)
+	switch hookType := hookVal.Type(); {
+	case hookType.ConvertibleTo(reflect.TypeOf(DecodeHookFuncType(nil))):
+		f := hookVal.Convert(reflect.TypeOf(DecodeHookFuncType(nil))).Interface().(DecodeHookFuncType)
+		d.hook = object.HookFunc(f)
+
+	case hookType.ConvertibleTo(reflect.TypeOf(DecodeHookFuncKind(nil))):
+		f := hookVal.Convert(reflect.TypeOf(DecodeHookFuncKind(nil))).Interface().(DecodeHookFuncKind)
+		d.hook = func(from reflect.Type, to reflect.Type, data any) (any, error) {
+			return f(from.Kind(), to.Kind(), data)
+		}
+
+	case hookType.ConvertibleTo(reflect.TypeOf(DecodeHookFuncValue(nil))):
+		f := hookVal.Convert(reflect.TypeOf(DecodeHookFuncValue(nil))).Interface().(DecodeHookFuncValue)
+		d.valueHook = func(from reflect.Value, to reflect.Value) (reflect.Value, error) {
+			data, err := f(from, to)
+			return reflect.ValueOf(data), err
+		}
+
+	default:
+		return fmt.Errorf("invalid decode hook signature: %T", hook)
+	}
+	return nil
+}
+
+// appendUnsetErrors adds to err an error for every struct with fields
+// missing from the input, as recorded in md.
+func appendUnsetErrors(err error, md *object.Metadata) error {
+	unset := make(map[string][]string)
+	for _, path := range md.Unset {
+		if md.UnsetReasons[path] != object.UnsetMissing {
+			continue
+		}
+		parent, name := "", path
+		if i := strings.LastIndex(path, "."); i >= 0 {
+			parent, name = path[:i], path[i+1:]
+		}
+		unset[parent] = append(unset[parent], name)
+	}
+	if len(unset) == 0 {
+		return err
+	}
+
+	var result *Error
+	if !errors.As(err, &result) {
+		result = &Error{}
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			result.Causes = append(result.Causes, err)
+		}
+	}
+
+	parents := make([]string, 0, len(unset))
+	for parent := range unset {
+		parents = append(parents, parent)
+	}
+	sort.Strings(parents)
+
+	for _, parent := range parents {
+		names := unset[parent]
+		sort.Strings(names)
+		unsetErr := fmt.Errorf("'%s' has unset fields: %s", parent, strings.Join(names, ", "))
+		result.Errors = append(result.Errors, unsetErr.Error())
+		result.Causes = append(result.Causes, unsetErr)
+	}
+	return result
+}
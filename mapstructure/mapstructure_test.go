@@ -0,0 +1,225 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type Person struct {
+	Name   string
+	Age    int
+	Emails []string
+	Extra  map[string]string
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]any{
+		"name":   "Mitchell",
+		"AGE":    91,
+		"emails": []string{"one", "two"},
+		"extra":  map[string]string{"twitter": "mitchellh"},
+	}
+
+	var result Person
+	if err := Decode(input, &result); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Person{
+		Name:   "Mitchell",
+		Age:    91,
+		Emails: []string{"one", "two"},
+		Extra:  map[string]string{"twitter": "mitchellh"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+
+	if err := Decode(input, result); err == nil {
+		t.Fatalf("expected an error for a non pointer result")
+	}
+	if err := Decode(map[string]any{"age": "91"}, &result); err == nil {
+		t.Fatalf("expected an error without weak decoding")
+	}
+	if err := WeakDecode(map[string]any{"age": "92"}, &result); err != nil || result.Age != 92 {
+		t.Fatalf("unexpected result %+v, error: %v", result, err)
+	}
+}
+
+func TestDecodeMetadata(t *testing.T) {
+	t.Parallel()
+
+	type Tagged struct {
+		Value string `mapstructure:"foo"`
+		Other string
+	}
+
+	var md Metadata
+	var result Tagged
+	if err := DecodeMetadata(map[string]any{"foo": "bar", "baz": 1}, &result, &md); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Value != "bar" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if !reflect.DeepEqual(md.Keys, []string{"foo"}) || !reflect.DeepEqual(md.Unused, []string{"baz"}) || !reflect.DeepEqual(md.Unset, []string{"Other"}) {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+}
+
+// The expected outputs below are the ones documented by mapstructure.
+func TestDecodeMetadataNested(t *testing.T) {
+	t.Parallel()
+
+	type Address struct {
+		City string `mapstructure:"city"`
+		Zip  string
+	}
+	type Tagged struct {
+		Name      string    `mapstructure:"name"`
+		Age       int       // untagged fields keep their Go name
+		Address   Address   `mapstructure:"address"`
+		Addresses []Address `mapstructure:"addresses"`
+	}
+
+	input := map[string]any{
+		"name":      "Mitchell",
+		"age":       91,
+		"address":   map[string]any{"city": "SF"},
+		"addresses": []any{map[string]any{"city": "LA", "zip": "90001"}},
+		"email":     "foo@bar.com",
+	}
+
+	var md Metadata
+	var result Tagged
+	if err := DecodeMetadata(input, &result, &md); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	sort.Strings(md.Keys)
+	expectedKeys := []string{"Age", "address", "address.city", "addresses", "addresses[0]", "addresses[0].Zip", "addresses[0].city", "name"}
+	if !reflect.DeepEqual(md.Keys, expectedKeys) {
+		t.Fatalf("expected keys %q, got %q", expectedKeys, md.Keys)
+	}
+	if !reflect.DeepEqual(md.Unused, []string{"email"}) || !reflect.DeepEqual(md.Unset, []string{"address.Zip"}) {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+}
+
+func TestDecodeStructToMap(t *testing.T) {
+	t.Parallel()
+
+	type Address struct {
+		City string `mapstructure:"city"`
+	}
+	type Tagged struct {
+		Name    string  `mapstructure:"name"`
+		Address Address `mapstructure:"address"`
+	}
+
+	var result map[string]interface{}
+	if err := Decode(Tagged{Name: "Mitchell", Address: Address{City: "SF"}}, &result); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"name":    "Mitchell",
+		"address": map[string]interface{}{"city": "SF"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, result)
+	}
+}
+
+func TestDecoderConfig(t *testing.T) {
+	t.Parallel()
+
+	type Base struct {
+		ID int
+	}
+	type Model struct {
+		Base
+		Name string
+	}
+
+	var squashed Model
+	decoder, err := NewDecoder(&DecoderConfig{Result: &squashed, Squash: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := decoder.Decode(map[string]any{"id": 1, "name": "n"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if squashed.ID != 1 || squashed.Name != "n" {
+		t.Fatalf("unexpected result %+v", squashed)
+	}
+
+	var nested Model
+	if err := Decode(map[string]any{"base": map[string]any{"id": 2}}, &nested); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if nested.ID != 2 {
+		t.Fatalf("embedded structs must be nested keys by default, got %+v", nested)
+	}
+
+	var unset Model
+	err = decode(map[string]any{"name": "n", "extra": 1}, &DecoderConfig{Result: &unset, ErrorUnset: true, ErrorUnused: true})
+	var derr *Error
+	if !errors.As(err, &derr) || len(derr.Errors) != 2 || !strings.Contains(err.Error(), "has unset fields: Base") {
+		t.Fatalf("expected unused and unset errors, got %v", err)
+	}
+
+	result := map[string]any{"stale": true}
+	err = decode(Model{Name: "n"}, &DecoderConfig{Result: &result, ZeroFields: true, IgnoreUntaggedFields: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(result) != 0 {
+		t.Fatalf("expected an emptied map without untagged fields, got %v", result)
+	}
+}
+
+func TestDecodeHook(t *testing.T) {
+	t.Parallel()
+
+	hooks := []DecodeHookFunc{
+		func(from reflect.Type, to reflect.Type, data any) (any, error) {
+			if from.Kind() == reflect.String {
+				return strings.ToUpper(data.(string)), nil
+			}
+			return data, nil
+		},
+		DecodeHookFuncKind(func(from reflect.Kind, to reflect.Kind, data any) (any, error) {
+			if from == reflect.String {
+				return strings.ToUpper(data.(string)), nil
+			}
+			return data, nil
+		}),
+		DecodeHookFuncValue(func(from reflect.Value, to reflect.Value) (any, error) {
+			if from.Kind() == reflect.String && to.Kind() == reflect.String {
+				return strings.ToUpper(from.String()), nil
+			}
+			return from.Interface(), nil
+		}),
+	}
+
+	for i, hook := range hooks {
+		var result Person
+		err := decode(map[string]any{"name": "m"}, &DecoderConfig{Result: &result, DecodeHook: hook})
+		if err != nil {
+			t.Fatalf("hook %d: unexpected error: %s", i, err)
+		}
+		if result.Name != "M" {
+			t.Fatalf("hook %d: expected 'M', got '%s'", i, result.Name)
+		}
+	}
+
+	if _, err := NewDecoder(&DecoderConfig{Result: &Person{}, DecodeHook: 1}); err == nil {
+		t.Fatalf("expected an error for an invalid hook")
+	}
+}
@@ -0,0 +1,150 @@
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSONMarshalerMode selects how source values implementing json.Marshaler
+// are assigned to map, interface and string targets.
+type JSONMarshalerMode int
+
+const (
+	// JSONMarshalerIgnore assigns json.Marshaler sources like any other
+	// value, through reflection. This is the default.
+	JSONMarshalerIgnore JSONMarshalerMode = iota
+
+	// JSONMarshalerParse assigns the parsed MarshalJSON output, e.g. the
+	// string of a time.Time, numbers being kept as json.Number.
+	JSONMarshalerParse
+
+	// JSONMarshalerRaw assigns the MarshalJSON output as a string to
+	// string targets and as a json.RawMessage to interface targets. Map
+	// targets receive the parsed output.
+	JSONMarshalerRaw
+)
+
+var (
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	rawMessageType      = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// asJSONMarshaler returns the json.Marshaler implementation of val. Non
+// addressable values are copied so that pointer receivers can be used.
+func asJSONMarshaler(val reflect.Value) (json.Marshaler, bool) {
+	if !val.IsValid() {
+		return nil, false
+	}
+
+	valType := val.Type()
+	if valType.Implements(jsonMarshalerType) {
+		if isPtrAble(val.Kind()) && val.IsNil() {
+			return nil, false
+		}
+		return val.Interface().(json.Marshaler), true
+	}
+
+	if val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface || !reflect.PointerTo(valType).Implements(jsonMarshalerType) {
+		return nil, false
+	}
+
+	if !val.CanAddr() {
+		copied := reflect.New(valType)
+		copied.Elem().Set(val)
+		val = copied.Elem()
+	}
+
+	return val.Addr().Interface().(json.Marshaler), true
+}
+
+// marshalJSONSource replaces a json.Marshaler source by its MarshalJSON
+// output according to the JSONMarshalers mode, for a target of kind
+// targetKind. It reports false when sourceVal is not replaced.
+func (a *assigner) marshalJSONSource(targetKind reflect.Kind, targetKey metaKey, sourceVal reflect.Value) (reflect.Value, bool, error) {
+	mode := a.config.JSONMarshalers
+	if mode == JSONMarshalerIgnore {
+		if !a.config.UseJSONInterfaces {
+			return sourceVal, false, nil
+		}
+		mode = JSONMarshalerParse
+	}
+
+	switch targetKind {
+	case reflect.Map, reflect.Interface, reflect.String:
+	default:
+		if !a.config.UseJSONInterfaces {
+			return sourceVal, false, nil
+		}
+	}
+
+	marshaler, ok := asJSONMarshaler(sourceVal)
+	if !ok {
+		return sourceVal, false, nil
+	}
+
+	data, err := marshaler.MarshalJSON()
+	if err != nil {
+		return sourceVal, false, fmt.Errorf("'%s' error marshaling '%s' to JSON: %w", targetKey.String(), sourceVal.Type(), err)
+	}
+
+	if mode == JSONMarshalerRaw {
+		switch targetKind {
+		case reflect.String:
+			return reflect.ValueOf(string(data)), true, nil
+		case reflect.Interface:
+			return reflect.ValueOf(json.RawMessage(data)), true, nil
+		}
+	}
+
+	parsed, err := decodeJSON(data)
+	if err != nil {
+		return sourceVal, false, fmt.Errorf("'%s' error parsing JSON of '%s': %w", targetKey.String(), sourceVal.Type(), err)
+	}
+	return reflect.ValueOf(parsed), true, nil
+}
+
+// assignJSONUnmarshaler assigns sourceVal to a target implementing
+// json.Unmarshaler through the JSON encoding of the source, for
+// UseJSONInterfaces. It reports false when the target doesn't implement
+// json.Unmarshaler.
+func (a *assigner) assignJSONUnmarshaler(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value) (bool, error) {
+	if targetVal.Kind() == reflect.Interface || !targetVal.CanAddr() || !targetVal.Addr().Type().Implements(jsonUnmarshalerType) {
+		return false, nil
+	}
+
+	var data []byte
+	var err error
+	if marshaler, ok := asJSONMarshaler(sourceVal); ok {
+		data, err = marshaler.MarshalJSON()
+	} else {
+		data, err = json.Marshal(sourceVal.Interface())
+	}
+	if err != nil {
+		return true, fmt.Errorf("'%s' error marshaling '%s' to JSON: %w", targetKey.String(), sourceVal.Type(), err)
+	}
+
+	if err := targetVal.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+		return true, fmt.Errorf("'%s' error unmarshaling JSON into '%s': %w", targetKey.String(), targetVal.Type(), err)
+	}
+	return true, nil
+}
+
+// assignRawMessage assigns the JSON encoding of sourceVal to a
+// json.RawMessage target, so that values decoded from JSON, e.g. with
+// DecodeFormat, can be kept as raw JSON and decoded later. Byte slice
+// sources are copied as is.
+func (a *assigner) assignRawMessage(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value) error {
+	if sourceVal.Kind() == reflect.Slice && sourceVal.Type().Elem().Kind() == reflect.Uint8 {
+		targetVal.SetBytes(append([]byte(nil), sourceVal.Bytes()...))
+		return nil
+	}
+
+	data, err := json.Marshal(sourceVal.Interface())
+	if err != nil {
+		return fmt.Errorf("'%s' error marshaling '%s' to JSON: %w", targetKey.String(), sourceVal.Type(), err)
+	}
+	targetVal.SetBytes(data)
+	return nil
+}
@@ -0,0 +1,125 @@
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testPoint struct {
+	X, Y int
+}
+
+func (p testPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int{p.X, p.Y})
+}
+
+func TestAssign_JSONMarshalers(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	type Source struct {
+		At    time.Time `json:"at"`
+		Point testPoint `json:"point"`
+	}
+
+	mode := func(m JSONMarshalerMode) func(c *AssignConfig) {
+		return func(c *AssignConfig) {
+			c.JSONMarshalers = m
+		}
+	}
+
+	var parsed map[string]any
+	if err := Assign(&parsed, Source{At: at, Point: testPoint{1, 2}}, mode(JSONMarshalerParse)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]any{
+		"at":    "2024-03-01T12:00:00Z",
+		"point": []any{json.Number("1"), json.Number("2")},
+	}
+	if !reflect.DeepEqual(parsed, expected) {
+		t.Fatalf("bad parsed: %#v", parsed)
+	}
+
+	var raw map[string]any
+	if err := Assign(&raw, Source{At: at, Point: testPoint{1, 2}}, mode(JSONMarshalerRaw)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(raw["point"], json.RawMessage("[1,2]")) {
+		t.Fatalf("bad raw: %#v", raw)
+	}
+
+	var s string
+	if err := Assign(&s, at, mode(JSONMarshalerRaw)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s != `"2024-03-01T12:00:00Z"` {
+		t.Fatalf("bad string: %s", s)
+	}
+
+	// Other targets are not affected
+	var copied Source
+	if err := Assign(&copied, Source{At: at, Point: testPoint{1, 2}}, mode(JSONMarshalerParse)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !copied.At.Equal(at) || copied.Point != (testPoint{1, 2}) {
+		t.Fatalf("bad copy: %#v", copied)
+	}
+}
+
+type testCelsius float64
+
+func (c *testCelsius) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	var f float64
+	if _, err := fmt.Sscanf(s, "%fC", &f); err != nil {
+		return err
+	}
+	*c = testCelsius(f)
+	return nil
+}
+
+func TestAssign_UseJSONInterfaces(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		At    time.Time   `json:"at"`
+		Temp  testCelsius `json:"temp"`
+		Point any         `json:"point"`
+	}
+
+	input := map[string]any{
+		"at":    "2024-03-01T12:00:00Z",
+		"temp":  "21.5C",
+		"point": testPoint{3, 4},
+	}
+
+	var result Target
+	if err := Assign(&result, input, func(c *AssignConfig) {
+		c.UseJSONInterfaces = true
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !result.At.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)) || result.Temp != 21.5 {
+		t.Fatalf("bad: %#v", result)
+	}
+	if !reflect.DeepEqual(result.Point, []any{json.Number("3"), json.Number("4")}) {
+		t.Fatalf("bad point: %#v", result.Point)
+	}
+
+	var bad Target
+	err := Assign(&bad, map[string]any{"temp": "hot"}, func(c *AssignConfig) {
+		c.UseJSONInterfaces = true
+	})
+	if err == nil || !strings.Contains(err.Error(), "error unmarshaling JSON into 'object.testCelsius'") {
+		t.Fatalf("expected unmarshal error, got %v", err)
+	}
+}
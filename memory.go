@@ -0,0 +1,97 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// LimitError is returned when the estimated memory allocated by an
+// assignment exceeds AssignConfig.MemoryLimit. The assignment is aborted
+// and the target is left partially assigned.
+type LimitError struct {
+	// Path is the path of the value that exceeded the limit.
+	Path string
+
+	// Limit is the configured MemoryLimit, in bytes.
+	Limit int
+
+	// Size is the estimated size of the allocations, in bytes, including
+	// the value that exceeded the limit.
+	Size int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("'%s' exceeds the memory limit of %d bytes (estimated %d bytes)", e.Path, e.Limit, e.Size)
+}
+
+// memoryBudget tracks the estimated allocations of a single call to
+// Assign, see AssignConfig.MemoryLimit.
+type memoryBudget struct {
+	limit int
+	size  int
+
+	// err is set once the limit is exceeded, the remaining values are
+	// then skipped.
+	err *LimitError
+}
+
+// withBudget returns a copy of the assigner tracking allocations in a new
+// memoryBudget, for a single call to Assign.
+func (a *assigner) withBudget() *assigner {
+	as := *a
+	as.budget = &memoryBudget{limit: a.config.MemoryLimit}
+	return &as
+}
+
+// spent reports whether the memory budget of the assignment is exhausted.
+func (a *assigner) spent() bool {
+	return a.budget != nil && a.budget.err != nil
+}
+
+// limitError returns the *LimitError of the assignment in place of err
+// once the memory budget is exhausted, as the other errors are incomplete.
+func (a *assigner) limitError(err error) error {
+	if a.spent() {
+		return a.budget.err
+	}
+	return err
+}
+
+// allocate charges the memory budget with the estimated size of the new
+// string, slice or map created when sourceVal is assigned to a targetVal
+// of kind targetKind. Elements are charged when they are assigned.
+func (a *assigner) allocate(targetVal reflect.Value, targetKind reflect.Kind, targetKey metaKey, sourceVal reflect.Value) error {
+	if a.budget == nil || !sourceVal.IsValid() {
+		return nil
+	}
+
+	size := 0
+	switch targetKind {
+	case reflect.String:
+		if isString(sourceVal.Kind()) || isArraySlice(sourceVal.Kind()) {
+			size = sourceVal.Len()
+		}
+	case reflect.Slice:
+		if isArraySlice(sourceVal.Kind()) || isString(sourceVal.Kind()) || sourceVal.Kind() == reflect.Map {
+			size = sourceVal.Len() * int(targetVal.Type().Elem().Size())
+		}
+	case reflect.Map:
+		if sourceVal.Kind() == reflect.Map || isArraySlice(sourceVal.Kind()) || sourceVal.Kind() == reflect.Struct {
+			mapType := targetVal.Type()
+			length := 0
+			if sourceVal.Kind() == reflect.Struct {
+				length = sourceVal.NumField()
+			} else {
+				length = sourceVal.Len()
+			}
+			size = length * int(mapType.Key().Size()+mapType.Elem().Size())
+		}
+	}
+
+	a.budget.size += size
+	if a.budget.size > a.budget.limit {
+		a.budget.err = &LimitError{Path: targetKey.String(), Limit: a.budget.limit, Size: a.budget.size}
+		return a.budget.err
+	}
+	return nil
+}
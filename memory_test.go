@@ -0,0 +1,61 @@
+package object
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAssign_MemoryLimit(t *testing.T) {
+	t.Parallel()
+
+	type Row struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+
+	source := map[string]any{
+		"rows": []any{
+			map[string]any{"name": "a", "tags": []any{"x"}},
+			map[string]any{"name": strings.Repeat("b", 4096), "tags": []any{"y"}},
+			map[string]any{"name": "c", "tags": []any{"z"}},
+		},
+	}
+
+	var result struct {
+		Rows []Row `json:"rows"`
+	}
+	err := Assign(&result, source, func(c *AssignConfig) {
+		c.MemoryLimit = 1024
+	})
+
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("expected a *LimitError, got %v", err)
+	}
+	if limitErr.Path != "Rows[1].Name" || limitErr.Limit != 1024 || limitErr.Size <= 1024 {
+		t.Fatalf("unexpected error %+v", limitErr)
+	}
+	if result.Rows[2].Name != "" {
+		t.Fatalf("expected the assignment to be aborted, got %+v", result.Rows[2])
+	}
+
+	var mapResult map[string][]int
+	err = Assign(&mapResult, map[string]any{"a": make([]any, 1000)}, func(c *AssignConfig) {
+		c.MemoryLimit = 1024
+	})
+	if !errors.As(err, &limitErr) || limitErr.Path != "a" {
+		t.Fatalf("expected a *LimitError for 'a', got %v", err)
+	}
+
+	result.Rows = nil
+	err = Assign(&result, source, func(c *AssignConfig) {
+		c.MemoryLimit = 1 << 20
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(result.Rows) != 3 || result.Rows[2].Name != "c" {
+		t.Fatalf("unexpected result %+v", result.Rows)
+	}
+}
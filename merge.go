@@ -0,0 +1,128 @@
+package object
+
+import (
+	"reflect"
+)
+
+// MergeStrategy selects how source values are merged into target values
+// that are already set.
+type MergeStrategy int
+
+const (
+	// MergeOverwrite replaces target values with source values, like
+	// Assign does by default.
+	MergeOverwrite MergeStrategy = iota
+
+	// MergeKeepExisting keeps the target values that are not zero. Maps,
+	// structs, slices and arrays are still merged element by element.
+	MergeKeepExisting
+)
+
+// SliceMergeStrategy selects how source slices are merged into non-empty
+// target slices and arrays, wherever they are held, including map values
+// and interfaces.
+type SliceMergeStrategy int
+
+const (
+	// SliceMergeTruncate merges the elements by index and truncates the
+	// target to the length of the source, like Assign does by default.
+	// Array elements past the length of the source are zeroed.
+	SliceMergeTruncate SliceMergeStrategy = iota
+
+	// SliceMergeIndex merges the elements by index and keeps the target
+	// elements past the length of the source.
+	SliceMergeIndex
+
+	// SliceMergeAppend appends the source elements to the target. Arrays
+	// can't grow and are merged like with SliceMergeIndex.
+	SliceMergeAppend
+)
+
+// MergeOption configures Merge. Any option of Assign can be used, see
+// MergeWith for the merge strategies.
+type MergeOption = func(c *AssignConfig)
+
+// Merge deep merges src, a map or a struct, into dst, which must be a
+// pointer. Maps and structs are merged key by key, including the maps and
+// structs held by interface typed maps such as map[string]any, the other
+// values according to the MergeStrategy and SliceMerge configuration.
+// Values of a different kind than the source, e.g. an int replaced by a
+// string in a map[string]any, are replaced whatever the strategy, except
+// MergeKeepExisting which keeps them. See MergeWith for a shorthand.
+func Merge(dst, src any, opts ...MergeOption) error {
+	configs := make([]func(c *AssignConfig), 0, len(opts)+1)
+	configs = append(configs, func(c *AssignConfig) {
+		c.MergeInterfaceMaps = true
+	})
+	return Assign(dst, src, append(configs, opts...)...)
+}
+
+// MergeWith returns an option selecting the merge strategies of values
+// and slices, see AssignConfig.MergeStrategy and AssignConfig.SliceMerge.
+func MergeWith(strategy MergeStrategy, slices SliceMergeStrategy) MergeOption {
+	return func(c *AssignConfig) {
+		c.MergeStrategy = strategy
+		c.SliceMerge = slices
+	}
+}
+
+// mergesMapValue reports whether existing, a map value, is decoded into
+// rather than replaced by sourceVal. Struct, map, slice and array values
+// are merged, as are the values of interfaces with methods, which decoded
+// values can't replace. Values of empty interfaces are replaced, so values
+// of another type can be stored, unless MergeInterfaceMaps merges them or
+// MergeKeepExisting must keep them.
+func (a *assigner) mergesMapValue(existing, sourceVal reflect.Value) bool {
+	if a.config.MergeStrategy == MergeKeepExisting {
+		return true
+	}
+
+	switch existing.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	case reflect.Interface:
+		if existing.Type().NumMethod() > 0 {
+			return true
+		}
+		return a.config.MergeInterfaceMaps && a.mergesHeldValue(existing.Elem(), sourceVal)
+	}
+	return false
+}
+
+// mergesHeldValue reports whether the value held by an interface typed
+// map is merged with sourceVal, see AssignConfig.MergeInterfaceMaps.
+func (a *assigner) mergesHeldValue(held, sourceVal reflect.Value) bool {
+	held, sourceVal = indirectValue(held), indirectValue(sourceVal)
+	if !held.IsValid() || !sourceVal.IsValid() {
+		return false
+	}
+
+	switch held.Kind() {
+	case reflect.Map, reflect.Struct:
+		return sourceVal.Kind() == reflect.Map || sourceVal.Kind() == reflect.Struct
+	case reflect.Slice, reflect.Array:
+		return isArraySlice(sourceVal.Kind()) && a.config.SliceMerge != SliceMergeTruncate
+	}
+	return false
+}
+
+// keepsExisting reports whether targetVal is set and must be kept by the
+// MergeKeepExisting strategy.
+func (a *assigner) keepsExisting(targetVal reflect.Value) bool {
+	if a.config.MergeStrategy != MergeKeepExisting || isZeroValue(targetVal) {
+		return false
+	}
+
+	val := indirectValue(targetVal)
+	if !val.IsValid() {
+		return false
+	}
+
+	switch val.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return false
+	case reflect.Struct:
+		return !hasExportedFields(val.Type())
+	}
+	return true
+}
@@ -0,0 +1,206 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	type Server struct {
+		Host  string
+		Port  int
+		Tags  []string
+		Extra map[string]any
+	}
+
+	base := func() Server {
+		return Server{
+			Host:  "localhost",
+			Tags:  []string{"a", "b", "c"},
+			Extra: map[string]any{"debug": true},
+		}
+	}
+	override := map[string]any{
+		"host":  "example.com",
+		"port":  8080,
+		"tags":  []string{"x"},
+		"extra": map[string]any{"debug": false, "trace": true},
+	}
+
+	tests := []struct {
+		name     string
+		configs  []func(c *AssignConfig)
+		expected Server
+	}{
+		{
+			name: "default",
+			expected: Server{
+				Host:  "example.com",
+				Port:  8080,
+				Tags:  []string{"x"},
+				Extra: map[string]any{"debug": false, "trace": true},
+			},
+		},
+		{
+			name:    "keep existing",
+			configs: []func(c *AssignConfig){MergeWith(MergeKeepExisting, SliceMergeIndex)},
+			expected: Server{
+				Host:  "localhost",
+				Port:  8080,
+				Tags:  []string{"a", "b", "c"},
+				Extra: map[string]any{"debug": true, "trace": true},
+			},
+		},
+		{
+			name:    "merge slices by index",
+			configs: []func(c *AssignConfig){MergeWith(MergeOverwrite, SliceMergeIndex)},
+			expected: Server{
+				Host:  "example.com",
+				Port:  8080,
+				Tags:  []string{"x", "b", "c"},
+				Extra: map[string]any{"debug": false, "trace": true},
+			},
+		},
+		{
+			name:    "append slices",
+			configs: []func(c *AssignConfig){MergeWith(MergeOverwrite, SliceMergeAppend)},
+			expected: Server{
+				Host:  "example.com",
+				Port:  8080,
+				Tags:  []string{"a", "b", "c", "x"},
+				Extra: map[string]any{"debug": false, "trace": true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		server := base()
+		if err := Merge(&server, override, test.configs...); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		if !reflect.DeepEqual(server, test.expected) {
+			t.Fatalf("%s: expected %+v, got %+v", test.name, test.expected, server)
+		}
+	}
+
+	// Typed maps skip the fast path to keep existing values.
+	labels := map[string]string{"env": "dev"}
+	err := Merge(&labels, map[string]string{"env": "prod", "team": "core"}, MergeWith(MergeKeepExisting, SliceMergeTruncate))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(labels, map[string]string{"env": "dev", "team": "core"}) {
+		t.Fatalf("bad: %v", labels)
+	}
+}
+
+func TestMerge_InterfaceMaps(t *testing.T) {
+	t.Parallel()
+
+	dst := map[string]any{
+		"count":  1,
+		"server": map[string]any{"host": "localhost", "port": 80},
+		"tags":   []any{"a", "b"},
+	}
+	src := map[string]any{
+		"count":  "one",
+		"server": map[string]any{"port": "http"},
+		"tags":   []any{"c"},
+	}
+	if err := Merge(&dst, src, MergeWith(MergeOverwrite, SliceMergeAppend)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Nested maps are merged, values of another type are replaced
+	expected := map[string]any{
+		"count":  "one",
+		"server": map[string]any{"host": "localhost", "port": "http"},
+		"tags":   []any{"a", "b", "c"},
+	}
+	if !reflect.DeepEqual(dst, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, dst)
+	}
+
+	// Assign replaces them
+	if err := Assign(&dst, map[string]any{"server": map[string]any{"port": 443}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if server := dst["server"]; !reflect.DeepEqual(server, map[string]any{"port": 443}) {
+		t.Fatalf("expected the server to be replaced, got %#v", server)
+	}
+}
+
+func TestAssign_NestedArrayMerge(t *testing.T) {
+	t.Parallel()
+
+	prefilled := func() map[string][2]Basic {
+		return map[string][2]Basic{
+			"a": {{Vstring: "a0", Vint: 1}, {Vstring: "a1", Vint: 2}},
+		}
+	}
+	source := map[string]any{
+		"a": []any{map[string]any{"vstring": "new"}},
+		"b": []any{map[string]any{"vint": 3}, map[string]any{"vint": 4}},
+	}
+
+	// Elements are merged by index, the rest is zeroed by default
+	result := prefilled()
+	if err := Assign(&result, source); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string][2]Basic{
+		"a": {{Vstring: "new", Vint: 1}, {}},
+		"b": {{Vint: 3}, {Vint: 4}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+
+	// SliceMergeIndex keeps the elements past the source
+	result = prefilled()
+	if err := Assign(&result, source, MergeWith(MergeOverwrite, SliceMergeIndex)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected["a"] = [2]Basic{{Vstring: "new", Vint: 1}, {Vstring: "a1", Vint: 2}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+
+	// Arrays held by interfaces are merged alike
+	var held struct {
+		A any
+	}
+	held.A = [3]int{1, 2, 3}
+	if err := Assign(&held, map[string]any{"a": []any{"7"}}, MergeWith(MergeOverwrite, SliceMergeIndex), func(c *AssignConfig) {
+		c.WeaklyTypedInput = true
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if held.A != [3]int{7, 2, 3} {
+		t.Fatalf("expected [7 2 3], got %v", held.A)
+	}
+
+	var field struct {
+		Values any
+	}
+	field.Values = [2]string{"x", "y"}
+	if err := Assign(&field, map[string]any{"values": []string{"z"}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if field.Values != [2]string{"z", ""} {
+		t.Fatalf("expected [z ], got %v", field.Values)
+	}
+
+	// ZeroFields replaces the array
+	result = prefilled()
+	if err := Assign(&result, map[string]any{"a": []any{map[string]any{"vint": 5}}}, MergeWith(MergeOverwrite, SliceMergeIndex), func(c *AssignConfig) {
+		c.ZeroFields = true
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result["a"] != [2]Basic{{Vint: 5}, {}} {
+		t.Fatalf("unexpected result %+v", result["a"])
+	}
+}
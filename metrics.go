@@ -0,0 +1,51 @@
+package object
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+// Metrics observes the assignments performed by Assign, e.g. to export
+// decode latency and error counts to a monitoring system.
+type Metrics interface {
+	// ObserveDecode is called once per Assign call with its duration, the
+	// number of target keys assigned and the number of errors reported.
+	ObserveDecode(duration time.Duration, fields int, errors int)
+}
+
+// MetricsFunc is an adapter to use an ordinary function as Metrics.
+type MetricsFunc func(duration time.Duration, fields int, errors int)
+
+// ObserveDecode calls f(duration, fields, errors).
+func (f MetricsFunc) ObserveDecode(duration time.Duration, fields int, errors int) {
+	f(duration, fields, errors)
+}
+
+// assignObserved is assignRoot reporting to the configured Metrics. The
+// assigned keys are counted by a copy of the assigner, see addMetaKey.
+func (a *assigner) assignObserved(targetVal, sourceVal reflect.Value) error {
+	keys := 0
+	as := *a
+	as.keys = &keys
+
+	start := time.Now()
+	err := as.assignRoot(targetVal, sourceVal)
+	duration := time.Since(start)
+
+	a.config.Metrics.ObserveDecode(duration, keys, errorCount(err))
+	return err
+}
+
+// errorCount returns the number of errors reported by err.
+func errorCount(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var e *Error
+	if errors.As(err, &e) && len(e.Errors) > 0 {
+		return len(e.Errors)
+	}
+	return 1
+}
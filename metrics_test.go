@@ -0,0 +1,72 @@
+package object
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssign_Metrics(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Name string
+		Port int
+	}
+
+	var calls, fields, errs int
+	var total time.Duration
+	withMetrics := func(c *AssignConfig) {
+		c.Metrics = MetricsFunc(func(duration time.Duration, f int, e int) {
+			calls++
+			total += duration
+			fields = f
+			errs = e
+		})
+	}
+
+	var config Config
+	if err := Assign(&config, map[string]any{"name": "svc", "port": 80}, withMetrics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls != 1 || fields != 2 || errs != 0 {
+		t.Fatalf("expected 1 call with 2 fields and 0 errors, got %d calls, %d fields, %d errors", calls, fields, errs)
+	}
+	if total < 0 {
+		t.Fatalf("expected a non negative duration, got %s", total)
+	}
+
+	input := map[string]any{"name": []int{1}, "port": "http"}
+	if err := Assign(&config, input, withMetrics); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if calls != 2 || errs != 2 {
+		t.Fatalf("expected 2 calls and 2 errors, got %d calls, %d errors", calls, errs)
+	}
+
+	// A configured Metadata is still filled.
+	var md Metadata
+	withMetadata := func(c *AssignConfig) {
+		c.Metadata = &md
+	}
+	if err := Assign(&config, map[string]any{"name": "api"}, withMetrics, withMetadata); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fields != 1 || len(md.Keys) != 1 || md.Keys[0] != "Name" {
+		t.Fatalf("expected 1 field recorded in metadata, got %d fields, keys %v", fields, md.Keys)
+	}
+
+	// Metrics don't bypass the Cache, hits report the cached field count.
+	cache := NewCache(0)
+	withCache := func(c *AssignConfig) {
+		c.Cache = cache
+	}
+	for i := 0; i < 2; i++ {
+		config = Config{}
+		if err := Assign(&config, map[string]any{"name": "svc", "port": 80}, withMetrics, withCache); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if cache.Len() != 1 || fields != 2 || config.Port != 80 {
+			t.Fatalf("expected a cached result with 2 fields, got %d entries, %d fields, %+v", cache.Len(), fields, config)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package object
+
+import (
+	"reflect"
+	"strings"
+)
+
+// MultiValueMode selects how string slices, such as the values of a
+// url.Values or an http.Header, are assigned to scalar targets. Slice
+// targets always receive every value.
+type MultiValueMode int
+
+const (
+	// MultiValueNone assigns string slices like any other slice, only
+	// WeaklyTypedInput accepts them for scalar targets.
+	MultiValueNone MultiValueMode = iota
+
+	// MultiValueFirst assigns the first value.
+	MultiValueFirst
+
+	// MultiValueLast assigns the last value.
+	MultiValueLast
+
+	// MultiValueJoin assigns the values joined with
+	// AssignConfig.MultiValueSeparator.
+	MultiValueJoin
+)
+
+// multiValue returns the value selected by the MultiValues mode when
+// sourceVal is a slice of strings assigned to a scalar target. Empty slices
+// select no value.
+func (a *assigner) multiValue(targetKind reflect.Kind, sourceVal reflect.Value) (value reflect.Value, ok bool) {
+	if a.config.MultiValues == MultiValueNone || !isScalar(targetKind) || !isArraySlice(sourceVal.Kind()) {
+		return reflect.Value{}, false
+	}
+
+	values, ok := stringValues(sourceVal)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	switch {
+	case len(values) == 0:
+		return reflect.Value{}, true
+	case a.config.MultiValues == MultiValueFirst:
+		return reflect.ValueOf(values[0]), true
+	case a.config.MultiValues == MultiValueLast:
+		return reflect.ValueOf(values[len(values)-1]), true
+	default:
+		return reflect.ValueOf(strings.Join(values, a.config.MultiValueSeparator)), true
+	}
+}
+
+// stringValues returns the elements of a slice or array of strings, or of
+// interfaces holding strings.
+func stringValues(sourceVal reflect.Value) ([]string, bool) {
+	values := make([]string, sourceVal.Len())
+	for i := range values {
+		elem := sourceVal.Index(i)
+		if elem.Kind() == reflect.Interface {
+			elem = elem.Elem()
+		}
+		if !elem.IsValid() || !isString(elem.Kind()) {
+			return nil, false
+		}
+		values[i] = elem.String()
+	}
+	return values, true
+}
@@ -0,0 +1,67 @@
+package object
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestAssign_MultiValues(t *testing.T) {
+	t.Parallel()
+
+	type Query struct {
+		Name string   `json:"name"`
+		Page int      `json:"page"`
+		Tags []string `json:"tags"`
+		Sort string   `json:"sort"`
+	}
+
+	query := url.Values{
+		"name": {"first", "second"},
+		"page": {"2", "3"},
+		"tags": {"a", "b"},
+		"sort": {},
+	}
+
+	tests := []struct {
+		mode     MultiValueMode
+		expected Query
+	}{
+		{MultiValueFirst, Query{Name: "first", Page: 2, Tags: []string{"a", "b"}}},
+		{MultiValueLast, Query{Name: "second", Page: 3, Tags: []string{"a", "b"}}},
+	}
+
+	for _, test := range tests {
+		var result Query
+		err := Assign(&result, query, func(c *AssignConfig) {
+			c.MultiValues = test.mode
+			c.NumberStrings = true
+		})
+		if err != nil {
+			t.Fatalf("mode %d: unexpected error: %s", test.mode, err)
+		}
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Fatalf("mode %d: expected %+v, got %+v", test.mode, test.expected, result)
+		}
+	}
+
+	var joined struct {
+		Name string `json:"name"`
+	}
+	err := Assign(&joined, map[string]any{"name": []any{"a", "b", "c"}}, func(c *AssignConfig) {
+		c.MultiValues = MultiValueJoin
+		c.MultiValueSeparator = ","
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if joined.Name != "a,b,c" {
+		t.Fatalf("expected 'a,b,c', got '%s'", joined.Name)
+	}
+
+	// Without a mode, string slices aren't accepted by scalar targets.
+	var result Query
+	if err := Assign(&result, query); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
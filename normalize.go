@@ -0,0 +1,107 @@
+package object
+
+import (
+	"reflect"
+	"sort"
+)
+
+// NormalizeKeys returns a copy of v in which every map with non string keys
+// (such as the map[any]any values produced by YAML decoders) is converted,
+// at any depth, into a map[string]any. Keys are converted to strings with
+// the same rules used for metadata keys. Maps and slices that may hold such
+// maps, such as map[string]any, []any or []map[any]any, are normalized as
+// well; any other value is returned as is.
+func NormalizeKeys(v any) any {
+	if v == nil {
+		return nil
+	}
+	return normalizeKeys(reflect.ValueOf(v))
+}
+
+func normalizeKeys(val reflect.Value) any {
+	switch val.Kind() {
+	case reflect.Interface:
+		if val.IsNil() {
+			return nil
+		}
+		return normalizeKeys(val.Elem())
+
+	case reflect.Map:
+		if !holdsKeyMaps(val.Type(), nil) {
+			return val.Interface()
+		}
+		if val.IsNil() {
+			return map[string]any(nil)
+		}
+
+		normalized := make(map[string]any, val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			normalized[mapKeyString(iter.Key())] = normalizeKeys(iter.Value())
+		}
+		return normalized
+
+	case reflect.Slice:
+		if !holdsKeyMaps(val.Type(), nil) {
+			return val.Interface()
+		}
+		if val.IsNil() {
+			return []any(nil)
+		}
+
+		normalized := make([]any, val.Len())
+		for i := range normalized {
+			normalized[i] = normalizeKeys(val.Index(i))
+		}
+		return normalized
+
+	default:
+		return val.Interface()
+	}
+}
+
+// holdsKeyMaps reports whether values of typ may be or hold maps with non
+// string keys, or interfaces which may hold them, through maps and slices.
+func holdsKeyMaps(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	switch typ.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Map, reflect.Slice:
+		if typ.Kind() == reflect.Map && typ.Key().Kind() != reflect.String {
+			return true
+		}
+		if visited[typ] {
+			return false
+		}
+		if visited == nil {
+			visited = map[reflect.Type]bool{}
+		}
+		visited[typ] = true
+		return holdsKeyMaps(typ.Elem(), visited)
+	}
+	return false
+}
+
+// normalizedKeys maps the keys of the source map sourceVal normalized by
+// the SourceKeyNormalizer to the keys themselves. When several keys are
+// normalized alike, the first one in key order wins.
+func (a *assigner) normalizedKeys(sourceVal reflect.Value) map[string]reflect.Value {
+	keys := sortedMapKeys(sourceVal)
+	normalized := make(map[string]reflect.Value, len(keys))
+	for _, k := range keys {
+		name := a.config.SourceKeyNormalizer(mapKeyString(k))
+		if _, exist := normalized[name]; !exist {
+			normalized[name] = k
+		}
+	}
+	return normalized
+}
+
+// sortedMapKeys returns the keys of the map val in key order.
+func sortedMapKeys(val reflect.Value) []reflect.Value {
+	keys := val.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return mapKeyString(keys[i]) < mapKeyString(keys[j])
+	})
+	return keys
+}
@@ -0,0 +1,193 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a single step of a path such as "a.b[2].c".
+type pathSegment struct {
+	// key is the map key or field name of the segment. For index segments
+	// it holds the index text.
+	key string
+
+	// index is the slice or array index of index segments.
+	index int
+
+	// isIndex reports whether the segment was written as [n].
+	isIndex bool
+}
+
+func (seg pathSegment) String() string {
+	if seg.isIndex {
+		return "[" + seg.key + "]"
+	}
+	return seg.key
+}
+
+// PathSegmentKind is the kind of a PathSegment.
+type PathSegmentKind int
+
+const (
+	// PathField is a struct field, or a key written without brackets.
+	PathField PathSegmentKind = iota
+
+	// PathMapKey is a map key written in brackets, e.g. "[x.y]".
+	PathMapKey
+
+	// PathIndex is a slice or array index, e.g. "[2]".
+	PathIndex
+)
+
+// PathSegment is a single step of a path such as "Items[2].Name".
+type PathSegment struct {
+	Kind PathSegmentKind
+
+	// Name is the field name or map key, or the index text of PathIndex
+	// segments.
+	Name string
+
+	// Index is the index of PathIndex segments.
+	Index int
+}
+
+// SplitPath splits a path, as reported by errors and Metadata, into its
+// segments. Map keys are written in brackets, except at the root where
+// they can't be told apart from struct fields, and numeric map keys are
+// reported as PathIndex.
+func SplitPath(path string) ([]PathSegment, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	segments := make([]PathSegment, 0, strings.Count(path, ".")+1)
+	rest := path
+	for rest != "" {
+		switch rest[0] {
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path '%s': missing ']'", path)
+			}
+			key := rest[1:end]
+			if key == "" {
+				return nil, fmt.Errorf("invalid path '%s': empty brackets", path)
+			}
+			seg := PathSegment{Kind: PathMapKey, Name: key}
+			if i, err := strconv.Atoi(key); err == nil {
+				seg.Kind = PathIndex
+				seg.Index = i
+			}
+			segments = append(segments, seg)
+			rest = rest[end+1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path '%s': empty segment", path)
+			}
+			segments = append(segments, PathSegment{Kind: PathField, Name: rest[:end]})
+			rest = rest[end:]
+		}
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+			if rest == "" {
+				return nil, fmt.Errorf("invalid path '%s': trailing '.'", path)
+			}
+		}
+	}
+
+	return segments, nil
+}
+
+// parsePath splits a path such as "a.b[2].c" into its segments. Bracketed
+// segments holding non numeric text (e.g. "a[b.c]") are map keys which may
+// contain dots.
+func parsePath(path string) ([]pathSegment, error) {
+	split, err := SplitPath(path)
+	if err != nil || split == nil {
+		return nil, err
+	}
+
+	segments := make([]pathSegment, len(split))
+	for i, seg := range split {
+		segments[i] = pathSegment{key: seg.Name}
+		if seg.Kind == PathIndex {
+			segments[i].index = seg.Index
+			segments[i].isIndex = true
+		}
+	}
+	return segments, nil
+}
+
+// lookupPath follows segments from val through pointers, interfaces, maps,
+// slices, arrays and structs. It reports false when a segment can't be
+// resolved.
+func lookupPath(val reflect.Value, segments []pathSegment) (reflect.Value, bool) {
+	return defaultAssigner.lookupPath(val, segments)
+}
+
+func (a *assigner) lookupPath(val reflect.Value, segments []pathSegment) (reflect.Value, bool) {
+	for _, seg := range segments {
+		val = indirectValue(val)
+		if !val.IsValid() {
+			return reflect.Value{}, false
+		}
+
+		switch val.Kind() {
+		case reflect.Map:
+			key := reflect.New(val.Type().Key()).Elem()
+			if err := a.assignKey(key, reflect.ValueOf(seg.key)); err != nil {
+				return reflect.Value{}, false
+			}
+			val = val.MapIndex(key)
+			if !val.IsValid() {
+				return reflect.Value{}, false
+			}
+		case reflect.Slice, reflect.Array:
+			index, err := strconv.Atoi(seg.key)
+			if err != nil || index < 0 || index >= val.Len() {
+				return reflect.Value{}, false
+			}
+			val = val.Index(index)
+		case reflect.Struct:
+			field, ok := a.structField(val, seg.key, false)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			val = field
+		default:
+			return reflect.Value{}, false
+		}
+	}
+
+	return val, true
+}
+
+// indirectValue dereferences pointers and interfaces until it reaches a
+// concrete value. Nil pointers and interfaces yield an invalid value.
+func indirectValue(val reflect.Value) reflect.Value {
+	for val.IsValid() && (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) {
+		if val.IsNil() {
+			return reflect.Value{}
+		}
+		val = val.Elem()
+	}
+	return val
+}
+
+// joinPath appends key to path, bracketing keys that contain path
+// separators.
+func joinPath(path string, key string) string {
+	if strings.ContainsAny(key, ".[]") || key == "" {
+		return path + "[" + key + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
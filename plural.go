@@ -0,0 +1,64 @@
+package object
+
+import (
+	"reflect"
+	"strings"
+)
+
+// pluralVariants returns the singular and plural variants of the key
+// name, following the common English rules: "host" and "hosts", "box"
+// and "boxes", "address" and "addresses", "policy" and "policies".
+func pluralVariants(name string) []string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return []string{name[:len(name)-3] + "y", name[:len(name)-1]}
+	case strings.HasSuffix(name, "es") && len(name) > 2:
+		return []string{name[:len(name)-2], name[:len(name)-1]}
+	case strings.HasSuffix(name, "s") && len(name) > 1:
+		return []string{name[:len(name)-1], name + "es"}
+	case strings.HasSuffix(name, "y") && len(name) > 1:
+		return []string{name[:len(name)-1] + "ies", name + "s"}
+	}
+	return []string{name + "s", name + "es"}
+}
+
+// pluralValue returns the value of the source map sourceVal for the
+// first singular or plural variant of the key name found, see
+// PluralizeKeys, adjusted to the type of the field: single values are
+// wrapped into slices for slice and array fields, and scalar fields take
+// the first element of slices.
+func (a *assigner) pluralValue(sourceVal reflect.Value, name string, fieldType reflect.Type) (value reflect.Value, key string, ok bool) {
+	keyVal := reflect.New(sourceVal.Type().Key()).Elem()
+	for _, variant := range pluralVariants(name) {
+		if err := a.assignKey(keyVal, reflect.ValueOf(variant)); err != nil {
+			continue
+		}
+		if value = sourceVal.MapIndex(keyVal); value.IsValid() {
+			key = variant
+			break
+		}
+	}
+	if !value.IsValid() {
+		return reflect.Value{}, "", false
+	}
+
+	elem := value
+	if elem.Kind() == reflect.Interface {
+		elem = elem.Elem()
+	}
+	if !elem.IsValid() {
+		return value, key, true
+	}
+
+	fieldKind := indirectType(fieldType).Kind()
+	switch {
+	case isArraySlice(fieldKind) && !isArraySlice(elem.Kind()):
+		return a.wrapSlice(elem), key, true
+	case isScalar(fieldKind) && isArraySlice(elem.Kind()):
+		if elem.Len() == 0 {
+			return reflect.Value{}, "", false
+		}
+		return elem.Index(0), key, true
+	}
+	return value, key, true
+}
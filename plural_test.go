@@ -0,0 +1,94 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPluralVariants(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"host":      "hosts",
+		"hosts":     "host",
+		"box":       "boxes",
+		"boxes":     "box",
+		"policy":    "policies",
+		"policies":  "policy",
+		"address":   "addresses",
+		"addresses": "address",
+	}
+	for name, expected := range tests {
+		found := false
+		for _, variant := range pluralVariants(name) {
+			found = found || variant == expected
+		}
+		if !found {
+			t.Fatalf("%s: expected '%s' in %v", name, expected, pluralVariants(name))
+		}
+	}
+}
+
+func TestAssign_PluralizeKeys(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Hosts    []string `json:"hosts"`
+		Host     string   `json:"host"`
+		Policies []int    `json:"policies"`
+		Port     int      `json:"port"`
+	}
+
+	pluralize := func(c *AssignConfig) {
+		c.PluralizeKeys = true
+	}
+
+	var result Config
+	err := Assign(&result, map[string]any{
+		"host":   "a",
+		"hosts":  []any{"b", "c"},
+		"policy": 1,
+		"ports":  []int{},
+	}, pluralize)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Exact matches win, empty slices leave scalars unset
+	expected := Config{Hosts: []string{"b", "c"}, Host: "a", Policies: []int{1}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+
+	var md Metadata
+	result = Config{}
+	err = Assign(&result, map[string]any{"host": "a", "policies": []any{2, 3}}, pluralize, func(c *AssignConfig) {
+		c.Metadata = &md
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected = Config{Hosts: []string{"a"}, Host: "a", Policies: []int{2, 3}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+	if len(md.Unused) != 0 {
+		t.Fatalf("unexpected unused keys %v", md.Unused)
+	}
+
+	var single struct {
+		Host string `json:"host"`
+	}
+	if err := Assign(&single, map[string]any{"hosts": []string{"x", "y"}}, pluralize); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if single.Host != "x" {
+		t.Fatalf("expected 'x', got '%s'", single.Host)
+	}
+
+	// Without the option variants don't match
+	single.Host = ""
+	if err := Assign(&single, map[string]any{"hosts": "x"}); err != nil || single.Host != "" {
+		t.Fatalf("unexpected result %+v, error: %v", single, err)
+	}
+}
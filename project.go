@@ -0,0 +1,73 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Project reshapes src into a new map in one pass. spec maps output paths
+// to source paths, e.g. {"name": "user.profile.displayName"}, using the
+// path syntax of Document; output paths may be nested ("owner.id") and an
+// empty source path selects src itself. Structs reachable from src are
+// read through their keys as Assign would produce them, see configs.
+//
+// Source paths that don't resolve are left out of the result, invalid
+// paths are reported as an error.
+func Project(src any, spec map[string]string, configs ...func(c *AssignConfig)) (map[string]any, error) {
+	sourceVal, err := genericSource(src, configs)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := make([]string, 0, len(spec))
+	for output := range spec {
+		outputs = append(outputs, output)
+	}
+	sort.Strings(outputs)
+
+	result := Document{}
+	errors := make([]error, 0)
+	for _, output := range outputs {
+		segments, err := parsePath(spec[output])
+		if err != nil {
+			errors = appendErrors(errors, fmt.Errorf("'%s' %w", output, err))
+			continue
+		}
+
+		val, ok := lookupPath(sourceVal, segments)
+		if !ok {
+			continue
+		}
+
+		var value any
+		if val.IsValid() {
+			value = val.Interface()
+		}
+
+		if err := result.Set(output, value); err != nil {
+			errors = appendErrors(errors, fmt.Errorf("'%s' %w", output, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return nil, newError(errors, nil)
+	}
+
+	return result, nil
+}
+
+// genericSource returns src with the structs it holds converted to maps,
+// so it can be traversed by lookupPath.
+func genericSource(src any, configs []func(c *AssignConfig)) (reflect.Value, error) {
+	configs = append(configs[:len(configs):len(configs)], func(c *AssignConfig) {
+		c.DeepInterfaceMaps = true
+	})
+	a := defaultAssigner.withConfig(configs...)
+
+	sourceVal := reflect.ValueOf(src)
+	if sourceVal.IsValid() && holdsStructs(sourceVal.Type()) {
+		return a.genericValue(sourceVal, "", "")
+	}
+	return sourceVal, nil
+}
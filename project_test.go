@@ -0,0 +1,54 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProject(t *testing.T) {
+	t.Parallel()
+
+	type Profile struct {
+		DisplayName string `json:"displayName"`
+	}
+
+	type User struct {
+		ID      int      `json:"id"`
+		Profile *Profile `json:"profile"`
+		Tags    []string `json:"tags"`
+	}
+
+	input := map[string]any{
+		"user":   User{ID: 7, Profile: &Profile{DisplayName: "Ada"}, Tags: []string{"admin", "ops"}},
+		"status": "active",
+	}
+
+	result, err := Project(input, map[string]string{
+		"name":      "user.profile.displayName",
+		"owner.id":  "user.id",
+		"firstTag":  "user.tags[0]",
+		"state":     "status",
+		"missing":   "user.profile.email",
+		"owner.raw": "user.tags",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]any{
+		"name":     "Ada",
+		"firstTag": "admin",
+		"state":    "active",
+		"owner": map[string]any{
+			"id":  7,
+			"raw": []string{"admin", "ops"},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	if _, err := Project(input, map[string]string{"bad": "user["}); err == nil {
+		t.Fatal("expected error for invalid path")
+	}
+}
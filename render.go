@@ -0,0 +1,129 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Render returns a copy of template in which the "${path}" placeholders of
+// string values are substituted with the values found at path in data,
+// using the path syntax of Document. A string made of a single placeholder
+// is replaced by the value itself, keeping its type, otherwise values are
+// formatted into the string. "$${" produces a literal "${". Nested maps and
+// slices of the template are rendered recursively, structs reachable from
+// data are read through their keys as Assign would produce them.
+//
+// Placeholders that don't resolve are reported as an error.
+func Render(template map[string]any, data any, configs ...func(c *AssignConfig)) (map[string]any, error) {
+	dataVal, err := genericSource(data, configs)
+	if err != nil {
+		return nil, err
+	}
+
+	errors := make([]error, 0)
+	result := renderValue(template, "", dataVal, &errors)
+	if len(errors) > 0 {
+		return nil, newError(errors, nil)
+	}
+
+	rendered, _ := result.(map[string]any)
+	return rendered, nil
+}
+
+// renderValue renders a template value found at path.
+func renderValue(value any, path string, dataVal reflect.Value, errors *[]error) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+		result := make(map[string]any, len(v))
+		for k, elem := range v {
+			result[k] = renderValue(elem, joinPath(path, k), dataVal, errors)
+		}
+		return result
+	case []any:
+		if v == nil {
+			return v
+		}
+		result := make([]any, len(v))
+		for i, elem := range v {
+			result[i] = renderValue(elem, path+"["+strconv.Itoa(i)+"]", dataVal, errors)
+		}
+		return result
+	case string:
+		rendered, err := renderString(v, dataVal)
+		if err != nil {
+			*errors = appendErrors(*errors, fmt.Errorf("'%s' %w", path, err))
+			return v
+		}
+		return rendered
+	}
+	return value
+}
+
+// renderString substitutes the placeholders of s.
+func renderString(s string, dataVal reflect.Value) (any, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	// A single placeholder keeps the type of the value
+	if strings.HasPrefix(s, "${") && strings.Index(s, "}") == len(s)-1 {
+		return resolvePlaceholder(s[2:len(s)-1], dataVal)
+	}
+
+	var b strings.Builder
+	rest := s
+	for {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			b.WriteString(rest)
+			break
+		}
+
+		if start > 0 && rest[start-1] == '$' {
+			b.WriteString(rest[:start-1])
+			b.WriteString("${")
+			rest = rest[start+2:]
+			continue
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated placeholder in '%s'", s)
+		}
+
+		value, err := resolvePlaceholder(rest[start+2:start+end], dataVal)
+		if err != nil {
+			return nil, err
+		}
+
+		b.WriteString(rest[:start])
+		if value != nil {
+			fmt.Fprint(&b, value)
+		}
+		rest = rest[start+end+1:]
+	}
+
+	return b.String(), nil
+}
+
+// resolvePlaceholder returns the value at path in dataVal.
+func resolvePlaceholder(path string, dataVal reflect.Value) (any, error) {
+	segments, err := parsePath(strings.TrimSpace(path))
+	if err != nil {
+		return nil, err
+	}
+
+	val, ok := lookupPath(dataVal, segments)
+	if !ok {
+		return nil, fmt.Errorf("unresolved placeholder '${%s}'", path)
+	}
+	if !val.IsValid() {
+		return nil, nil
+	}
+	return val.Interface(), nil
+}
@@ -0,0 +1,72 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	type Server struct {
+		Host  string `json:"host"`
+		Ports []int  `json:"ports"`
+	}
+
+	data := map[string]any{
+		"env":    "prod",
+		"server": Server{Host: "example.com", Ports: []int{80, 443}},
+	}
+
+	template := map[string]any{
+		"name": "app-${env}",
+		"port": "${server.ports[1]}",
+		"url":  "https://${server.host}:${server.ports[1]}/",
+		"raw":  "$${env}",
+		"nested": map[string]any{
+			"hosts": []any{"${server.host}", 42},
+		},
+	}
+
+	result, err := Render(template, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]any{
+		"name": "app-prod",
+		"port": 443,
+		"url":  "https://example.com:443/",
+		"raw":  "${env}",
+		"nested": map[string]any{
+			"hosts": []any{"example.com", 42},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	// The template is left untouched
+	if template["name"] != "app-${env}" {
+		t.Fatalf("template modified: %#v", template)
+	}
+}
+
+func TestRender_Unresolved(t *testing.T) {
+	t.Parallel()
+
+	template := map[string]any{
+		"a": "${missing}",
+		"b": map[string]any{"c": "x-${open"},
+	}
+
+	_, err := Render(template, map[string]any{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	derr, ok := err.(*Error)
+	if !ok || len(derr.Errors) != 2 {
+		t.Fatalf("expected 2 errors, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// parseScale parses the factor of a "scale=" tag option.
+func parseScale(text string, key metaKey) (float64, error) {
+	scale, err := strconv.ParseFloat(text, 64)
+	if err != nil || scale == 0 || math.IsInf(scale, 0) || math.IsNaN(scale) {
+		return 0, fmt.Errorf("'%s' invalid scale '%s'", key.String(), text)
+	}
+	return scale, nil
+}
+
+// assignScale assigns a field tagged with a scale: numbers are multiplied
+// by the scale before they are assigned. Anything else is assigned as
+// usual.
+func (a *assigner) assignScale(field fieldInfo, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) error {
+	scale, err := parseScale(field.Scale, targetKey)
+	if err != nil {
+		return err
+	}
+
+	source := indirectValue(sourceVal)
+	if source.IsValid() {
+		if isJsonNumber(source.Type()) || isString(source.Kind()) && (a.config.WeaklyTypedInput || a.config.NumberStrings) {
+			if number, ok := parseNumber(source.String()); ok {
+				source = number
+			}
+		}
+		if scaled, ok := scaleNumber(source, scale, false); ok {
+			sourceVal = scaled
+		}
+	}
+
+	return a.assignContext(field.fieldVal, targetKey, sourceVal, sourceKey, &field)
+}
+
+// scaledValue converts val for a field tagged with a scale when a struct
+// is assigned to a map: numbers are divided by the scale. Other values are
+// returned as is.
+func scaledValue(val reflect.Value, text string, key metaKey) (reflect.Value, error) {
+	scale, err := parseScale(text, key)
+	if err != nil {
+		return val, err
+	}
+	if scaled, ok := scaleNumber(indirectValue(val), scale, true); ok {
+		return scaled, nil
+	}
+	return val, nil
+}
+
+// scaleNumber multiplies, or divides, the number val by scale. The result
+// is an int64 when val is an integer and the result is exact, a float64
+// otherwise. It reports false when val is not a number.
+func scaleNumber(val reflect.Value, scale float64, divide bool) (reflect.Value, bool) {
+	if !val.IsValid() {
+		return val, false
+	}
+
+	var n *big.Int
+	switch {
+	case isInt(val.Kind()):
+		n = big.NewInt(val.Int())
+	case isUint(val.Kind()):
+		n = new(big.Int).SetUint64(val.Uint())
+	case isFloat(val.Kind()):
+		if divide {
+			return reflect.ValueOf(val.Float() / scale), true
+		}
+		return reflect.ValueOf(val.Float() * scale), true
+	default:
+		return val, false
+	}
+
+	if s := int64(scale); float64(s) == scale {
+		factor := big.NewInt(s)
+		if !divide {
+			if product := new(big.Int).Mul(n, factor); product.IsInt64() {
+				return reflect.ValueOf(product.Int64()), true
+			}
+		} else if quotient, remainder := new(big.Int).QuoRem(n, factor, new(big.Int)); remainder.Sign() == 0 && quotient.IsInt64() {
+			return reflect.ValueOf(quotient.Int64()), true
+		}
+	}
+
+	f, _ := new(big.Float).SetInt(n).Float64()
+	if divide {
+		f /= scale
+	} else {
+		f *= scale
+	}
+	if f == math.Trunc(f) && math.Abs(f) < math.MaxInt64 {
+		return reflect.ValueOf(int64(f)), true
+	}
+	return reflect.ValueOf(f), true
+}
+
+// parseNumber parses the text of a number into an int64 or a float64.
+func parseNumber(str string) (reflect.Value, bool) {
+	if i, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return reflect.ValueOf(i), true
+	}
+	if f, err := strconv.ParseFloat(str, 64); err == nil {
+		return reflect.ValueOf(f), true
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,69 @@
+package object
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAssign_Scale(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Memory  int64   `object:"memory_mb,scale=1048576"`
+		Ratio   float64 `object:"ratio_pct,scale=0.01"`
+		Limit   uint32  `object:"limit_k,scale=1000"`
+		Timeout int     `object:"timeout,scale=1000"`
+	}
+
+	tag := func(c *AssignConfig) {
+		c.TagName = "object"
+	}
+
+	input := map[string]any{
+		"memory_mb": 512,
+		"ratio_pct": json.Number("25"),
+		"limit_k":   1.5,
+		"timeout":   "x",
+	}
+
+	var result Target
+	err := Assign(&result, input, tag)
+	if err == nil || !strings.Contains(err.Error(), "'Timeout'") {
+		t.Fatalf("expected error for non numeric value, got %v", err)
+	}
+
+	delete(input, "timeout")
+	result = Target{}
+	if err := Assign(&result, input, tag); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Target{Memory: 512 << 20, Ratio: 0.25, Limit: 1500}
+	if result != expected {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	// The inverse is applied on encode
+	var m map[string]any
+	if err := Assign(&m, expected, tag); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	encoded := map[string]any{
+		"memory_mb": int64(512),
+		"ratio_pct": 25.0,
+		"limit_k":   1.5,
+		"timeout":   int64(0),
+	}
+	if !reflect.DeepEqual(m, encoded) {
+		t.Fatalf("bad encoded: %#v", m)
+	}
+
+	type Bad struct {
+		Value int `object:"value,scale=abc"`
+	}
+	if err := Assign(&Bad{}, map[string]any{"value": 1}, tag); err == nil || !strings.Contains(err.Error(), "invalid scale 'abc'") {
+		t.Fatalf("expected invalid scale error, got %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package object
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// SliceMergeByKey returns an option that merges slices of structs by
+// matching their elements on key, see AssignConfig.SliceMergeKey.
+func SliceMergeByKey(key string) func(c *AssignConfig) {
+	return func(c *AssignConfig) {
+		c.SliceMergeKey = key
+	}
+}
+
+// normalizeSlice applies the configured slice post-processing steps
+// (CompactSlices, DedupeSlices and SortSlices, in that order) to slice.
+func (a *assigner) normalizeSlice(slice reflect.Value) reflect.Value {
+	if a.config.CompactSlices {
+		slice = compactSlice(slice)
+	}
+
+	if a.config.DedupeSlices {
+		slice = dedupeSlice(slice)
+	}
+
+	if a.config.SortSlices {
+		sortSlice(slice)
+	}
+
+	return slice
+}
+
+// compactSlice returns a slice without the zero value elements of slice.
+func compactSlice(slice reflect.Value) reflect.Value {
+	result := reflect.MakeSlice(slice.Type(), 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+		if isZeroValue(elem) {
+			continue
+		}
+		result = reflect.Append(result, elem)
+	}
+	return result
+}
+
+// dedupeSlice returns a slice without the duplicate elements of slice,
+// keeping the first occurrence of each element.
+func dedupeSlice(slice reflect.Value) reflect.Value {
+	result := reflect.MakeSlice(slice.Type(), 0, slice.Len())
+	seen := make(map[any]struct{}, slice.Len())
+
+	for i := 0; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+
+		if isComparable(elem) {
+			key := elem.Interface()
+			if _, exist := seen[key]; exist {
+				continue
+			}
+			seen[key] = struct{}{}
+			result = reflect.Append(result, elem)
+			continue
+		}
+
+		// Fall back to deep comparison for non comparable elements
+		duplicated := false
+		for j := 0; j < result.Len(); j++ {
+			if reflect.DeepEqual(result.Index(j).Interface(), elem.Interface()) {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			result = reflect.Append(result, elem)
+		}
+	}
+
+	return result
+}
+
+// isComparable reports whether the dynamic value of elem can be used as a
+// map key. Interfaces, including the ones nested in structs and arrays, may
+// hold values that aren't.
+func isComparable(elem reflect.Value) bool {
+	switch elem.Kind() {
+	case reflect.Interface:
+		return elem.IsNil() || isComparable(elem.Elem())
+	case reflect.Struct:
+		for i := 0; i < elem.NumField(); i++ {
+			if !isComparable(elem.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Array:
+		for i := 0; i < elem.Len(); i++ {
+			if !isComparable(elem.Index(i)) {
+				return false
+			}
+		}
+		return elem.Type().Comparable()
+	}
+	return elem.Type().Comparable()
+}
+
+// sortSlice sorts slice in place in ascending order. Slices whose element
+// type isn't ordered are left untouched.
+func sortSlice(slice reflect.Value) {
+	var less func(i, j int) bool
+
+	switch kind := slice.Type().Elem().Kind(); {
+	case isInt(kind):
+		less = func(i, j int) bool { return slice.Index(i).Int() < slice.Index(j).Int() }
+	case isUint(kind):
+		less = func(i, j int) bool { return slice.Index(i).Uint() < slice.Index(j).Uint() }
+	case isFloat(kind):
+		less = func(i, j int) bool { return slice.Index(i).Float() < slice.Index(j).Float() }
+	case isString(kind):
+		less = func(i, j int) bool { return slice.Index(i).String() < slice.Index(j).String() }
+	default:
+		return
+	}
+
+	sort.SliceStable(slice.Interface(), less)
+}
+
+// mergeSliceByKey merges sourceVal into the struct elements of targetVal
+// whose key value matches, and appends the source elements that
+// don't match any.
+func (a *assigner) mergeSliceByKey(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey, key string) error {
+	elemType := targetVal.Type().Elem()
+
+	// Index the target elements by key value
+	targetValSlice := reflect.MakeSlice(targetVal.Type(), targetVal.Len(), targetVal.Len())
+	reflect.Copy(targetValSlice, targetVal)
+
+	indexes := make(map[any]int, targetValSlice.Len())
+	for i := 0; i < targetValSlice.Len(); i++ {
+		if keyVal, ok := a.structKeyValue(targetValSlice.Index(i), key); ok && isComparable(keyVal) {
+			indexes[keyVal.Interface()] = i
+		}
+	}
+
+	errors := make([]error, 0)
+	collection := make([]*CollectionError, 0)
+	for i := 0; i < sourceVal.Len(); i++ {
+		sourceElem := sourceVal.Index(i)
+		sourceFieldKey := sourceKey.newChild(reflect.Slice, strconv.Itoa(i))
+
+		index, matched := a.matchKey(sourceElem, elemType, indexes, key)
+		if !matched {
+			index = targetValSlice.Len()
+			targetValSlice = reflect.Append(targetValSlice, reflect.Zero(elemType))
+		}
+
+		targetFieldKey := targetKey.newChild(reflect.Slice, strconv.Itoa(index))
+		if a.shouldSkipKey(targetFieldKey, sourceFieldKey) {
+			continue
+		}
+
+		if err := a.assign(targetValSlice.Index(index), targetFieldKey, sourceElem, sourceFieldKey); err != nil {
+			errors = appendErrors(errors, err)
+			collection = appendElementError(collection, targetFieldKey, index, "", err)
+			if a.config.FailFast {
+				break
+			}
+		}
+	}
+
+	targetVal.Set(a.normalizeSlice(targetValSlice))
+
+	if len(errors) > 0 {
+		return newError(errors, collection)
+	}
+
+	return nil
+}
+
+// matchKey returns the index of the target element whose key value equals
+// the key value of sourceElem, converted to the key type of elemType.
+func (a *assigner) matchKey(sourceElem reflect.Value, elemType reflect.Type, indexes map[any]int, key string) (int, bool) {
+	sourceKeyVal, ok := a.sourceKeyValue(sourceElem, key)
+	if !ok {
+		return 0, false
+	}
+
+	keyField, ok := a.keyField(indirectType(elemType), key)
+	if !ok {
+		return 0, false
+	}
+
+	keyVal := reflect.New(keyField.Type).Elem()
+	if err := a.keyAssigner.assign(keyVal, "", sourceKeyVal, ""); err != nil || !isComparable(keyVal) {
+		return 0, false
+	}
+
+	index, ok := indexes[keyVal.Interface()]
+	return index, ok
+}
+
+// structKeyValue returns the key field of a struct element.
+func (a *assigner) structKeyValue(elem reflect.Value, key string) (reflect.Value, bool) {
+	elem = reflect.Indirect(elem)
+	if !isStruct(elem.Kind()) {
+		return reflect.Value{}, false
+	}
+
+	for _, field := range a.flattenStruct(elem, false) {
+		if isMergeKey(field, key) {
+			return field.fieldVal, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// sourceKeyValue returns the key value of a source element,
+// which may be a map or a struct.
+func (a *assigner) sourceKeyValue(elem reflect.Value, key string) (reflect.Value, bool) {
+	elem = indirectValue(elem)
+	switch {
+	case isMap(elem.Kind()):
+		iter := elem.MapRange()
+		for iter.Next() {
+			if mapKeyString(iter.Key()) == key {
+				return iter.Value(), true
+			}
+		}
+	case isStruct(elem.Kind()):
+		return a.structKeyValue(elem, key)
+	}
+	return reflect.Value{}, false
+}
+
+// keyField returns the key field of a struct type.
+func (a *assigner) keyField(structType reflect.Type, key string) (reflect.StructField, bool) {
+	for _, field := range a.flattenStruct(reflect.New(structType).Elem(), false) {
+		if isMergeKey(field, key) {
+			return field.field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func isMergeKey(field fieldInfo, key string) bool {
+	return field.actualName == key || field.displayName == key
+}
+
+// sliceMergeKey returns the merge key of the "mergekey=" option of field,
+// or SliceMergeKey.
+func (a *assigner) sliceMergeKey(field *fieldInfo) string {
+	if field != nil && field.MergeKey != "" {
+		return field.MergeKey
+	}
+	return a.config.SliceMergeKey
+}
+
+// indirectType returns the element type of pointer types.
+func indirectType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Ptr {
+		return typ.Elem()
+	}
+	return typ
+}
@@ -0,0 +1,286 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssign_SliceNormalization(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Hosts []string
+		Ports []int
+		Tags  []any
+	}
+
+	input := map[string]any{
+		"hosts": []string{"b.example.com", "", "a.example.com", "b.example.com"},
+		"ports": []any{8080, 0, 443, 8080, 80},
+		"tags":  []any{"x", []string{"y"}, "x", []string{"y"}, nil},
+	}
+
+	var result Config
+	err := Assign(&result, input, func(c *AssignConfig) {
+		c.CompactSlices = true
+		c.DedupeSlices = true
+		c.SortSlices = true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Config{
+		Hosts: []string{"a.example.com", "b.example.com"},
+		Ports: []int{80, 443, 8080},
+		Tags:  []any{"x", []string{"y"}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, result)
+	}
+}
+
+func TestAssign_SliceNormalizationIndividually(t *testing.T) {
+	t.Parallel()
+
+	input := []int{3, 0, 1, 3}
+
+	cases := []struct {
+		name     string
+		config   func(c *AssignConfig)
+		expected []int
+	}{
+		{"none", func(c *AssignConfig) {}, []int{3, 0, 1, 3}},
+		{"compact", func(c *AssignConfig) { c.CompactSlices = true }, []int{3, 1, 3}},
+		{"dedupe", func(c *AssignConfig) { c.DedupeSlices = true }, []int{3, 0, 1}},
+		{"sort", func(c *AssignConfig) { c.SortSlices = true }, []int{0, 1, 3, 3}},
+	}
+
+	for _, tc := range cases {
+		var result []int
+		if err := Assign(&result, input, tc.config); err != nil {
+			t.Fatalf("%s: unexpected error: %s", tc.name, err)
+		}
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Fatalf("%s: expected: %#v, got: %#v", tc.name, tc.expected, result)
+		}
+	}
+}
+
+func TestAssign_SliceMergeByKey(t *testing.T) {
+	t.Parallel()
+
+	type Server struct {
+		ID   int    `json:"id"`
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+	type Config struct {
+		Servers []Server
+	}
+
+	config := Config{
+		Servers: []Server{
+			{ID: 1, Host: "a", Port: 80},
+			{ID: 2, Host: "b", Port: 80},
+		},
+	}
+
+	input := map[string]any{
+		"servers": []any{
+			map[string]any{"id": "2", "port": 8080},
+			map[string]any{"id": 3, "host": "c"},
+		},
+	}
+
+	if err := Assign(&config, input, SliceMergeByKey("id"), func(c *AssignConfig) {
+		c.WeaklyTypedInput = true
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []Server{
+		{ID: 1, Host: "a", Port: 80},
+		{ID: 2, Host: "b", Port: 8080},
+		{ID: 3, Host: "c"},
+	}
+	if !reflect.DeepEqual(config.Servers, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, config.Servers)
+	}
+
+	// Struct sources and pointer elements, matched by field name.
+	targets := []*Server{{ID: 1, Host: "a"}}
+	sources := []Server{{ID: 1, Host: "x"}, {ID: 4, Host: "y"}}
+	if err := Assign(&targets, sources, SliceMergeByKey("ID")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(targets) != 2 || targets[0].Host != "x" || targets[1].ID != 4 {
+		t.Fatalf("bad: %#v", targets)
+	}
+
+	// Without the option slices are merged by index.
+	byIndex := []Server{{ID: 1, Host: "a"}, {ID: 2, Host: "b"}}
+	if err := Assign(&byIndex, []map[string]any{{"id": 2}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(byIndex) != 1 || byIndex[0].Host != "a" || byIndex[0].ID != 2 {
+		t.Fatalf("bad: %#v", byIndex)
+	}
+}
+
+type sliceShape interface {
+	Area() float64
+}
+
+type sliceCircle struct {
+	R float64
+}
+
+func (c *sliceCircle) Area() float64 { return c.R * c.R }
+
+type sliceSquare struct {
+	S     float64
+	Color string
+}
+
+func (s sliceSquare) Area() float64 { return s.S * s.S }
+
+func TestAssign_InterfaceSliceElements(t *testing.T) {
+	t.Parallel()
+
+	shapes := []sliceShape{&sliceCircle{R: 1}, sliceSquare{S: 2, Color: "red"}}
+	source := []any{
+		map[string]any{"r": 5},
+		map[string]any{"s": 3},
+	}
+	if err := Assign(&shapes, source); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []sliceShape{&sliceCircle{R: 5}, sliceSquare{S: 3, Color: "red"}}
+	if !reflect.DeepEqual(shapes, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, shapes)
+	}
+
+	// Pointer elements are decoded in place.
+	circle := &sliceCircle{R: 1}
+	shapes = []sliceShape{circle}
+	if err := Assign(&shapes, []any{map[string]any{"r": 2}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if shapes[0] != circle || circle.R != 2 {
+		t.Fatalf("expected the existing element to be updated, got %#v", shapes[0])
+	}
+
+	// Arrays and map values are merged the same way.
+	array := [1]sliceShape{sliceSquare{S: 1, Color: "blue"}}
+	if err := Assign(&array, []any{map[string]any{"s": 4}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if array[0] != (sliceSquare{S: 4, Color: "blue"}) {
+		t.Fatalf("bad: %#v", array[0])
+	}
+
+	byName := map[string]sliceShape{"c": &sliceCircle{R: 1}}
+	if err := Assign(&byName, map[string]any{"c": map[string]any{"r": 3}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if byName["c"].(*sliceCircle).R != 3 {
+		t.Fatalf("bad: %#v", byName["c"])
+	}
+
+	// Elements without a concrete value can't be decoded into.
+	shapes = []sliceShape{&sliceCircle{R: 1}}
+	if err := Assign(&shapes, []any{map[string]any{"r": 1}, map[string]any{"r": 2}}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestAssign_MergeKeyTag(t *testing.T) {
+	t.Parallel()
+
+	type Server struct {
+		ID   int    `json:"id"`
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+	type Route struct {
+		Path    string `json:"path"`
+		Backend string `json:"backend"`
+	}
+	type Config struct {
+		Servers []Server `json:"servers,mergekey=id"`
+		Routes  []Route  `json:"routes,mergekey=path"`
+		Tags    []Server `json:"tags"`
+	}
+
+	config := Config{
+		Servers: []Server{{ID: 1, Host: "a", Port: 80}, {ID: 2, Host: "b", Port: 81}},
+		Routes:  []Route{{Path: "/", Backend: "web"}},
+		Tags:    []Server{{ID: 1, Host: "x"}, {ID: 2, Host: "y"}},
+	}
+
+	overlay := map[string]any{
+		"servers": []any{map[string]any{"id": 2, "port": 8081}, map[string]any{"id": 3, "host": "c"}},
+		"routes":  []any{map[string]any{"path": "/", "backend": "api"}},
+		"tags":    []any{map[string]any{"id": 2}},
+	}
+	if err := Assign(&config, overlay); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Config{
+		Servers: []Server{{ID: 1, Host: "a", Port: 80}, {ID: 2, Host: "b", Port: 8081}, {ID: 3, Host: "c"}},
+		Routes:  []Route{{Path: "/", Backend: "api"}},
+		// Slices without the option are merged by index.
+		Tags: []Server{{ID: 2, Host: "x"}},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, config)
+	}
+}
+
+func TestAssign_MergeKeyUnhashable(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		ID   any
+		Name string
+	}
+
+	items := []Item{{ID: []int{1}, Name: "a"}, {ID: 2, Name: "b"}}
+	source := []any{
+		map[string]any{"id": []int{1}, "name": "c"},
+		map[string]any{"id": 2, "name": "d"},
+	}
+	if err := Assign(&items, source, SliceMergeByKey("id")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Unhashable keys never match, their elements are appended.
+	expected := []Item{{ID: []int{1}, Name: "a"}, {ID: 2, Name: "d"}, {ID: []int{1}, Name: "c"}}
+	if !reflect.DeepEqual(items, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, items)
+	}
+}
+
+func TestAssign_DedupeUnhashableStructs(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		Name  string
+		Value any
+	}
+
+	input := []Item{{"a", []int{1}}, {"b", 2}, {"a", []int{1}}, {"b", 2}, {"a", []int{2}}}
+
+	var result []Item
+	if err := Assign(&result, input, func(c *AssignConfig) { c.DedupeSlices = true }); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []Item{{"a", []int{1}}, {"b", 2}, {"a", []int{2}}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, result)
+	}
+}
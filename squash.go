@@ -0,0 +1,147 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SquashMode selects which struct fields are squashed.
+type SquashMode int
+
+const (
+	// SquashEmbedded squashes embedded structs and fields tagged "squash",
+	// unless they are tagged "nosquash". This is the default.
+	SquashEmbedded SquashMode = iota
+
+	// SquashTagged only squashes fields tagged "squash", embedded structs
+	// are nested keys named by their tag or type name.
+	SquashTagged
+)
+
+// SquashCollisionPolicy selects how keys defined by several squashed or
+// embedded structs at the same depth are handled.
+type SquashCollisionPolicy int
+
+const (
+	// SquashCollisionFirst keeps the first field found for a key, which
+	// is arbitrary for fields at the same depth. Shallower fields always
+	// shadow deeper ones. This is the default.
+	SquashCollisionFirst SquashCollisionPolicy = iota
+
+	// SquashCollisionError fails the assignment with a descriptive error
+	// naming the colliding fields.
+	SquashCollisionError
+)
+
+// checkSquashCollisions returns an error listing the keys of typ that are
+// defined by more than one field at the same, shallowest, depth.
+func (a *assigner) checkSquashCollisions(typ reflect.Type, key metaKey) error {
+	// Each level records the level embedding it, so types embedded
+	// several times are all checked while cycles of embedded pointers
+	// aren't walked again.
+	type level struct {
+		typ    reflect.Type
+		depth  int
+		path   string
+		parent int
+	}
+
+	type definition struct {
+		depth int
+		paths []string
+	}
+
+	definitions := make(map[string]*definition)
+	queue := []level{{typ: typ, path: typ.Name(), parent: -1}}
+	embeds := func(index int, typ reflect.Type) bool {
+		for i := index; i >= 0; i = queue[i].parent {
+			if queue[i].typ == typ {
+				return true
+			}
+		}
+		return false
+	}
+
+	for index := 0; index < len(queue); index++ {
+		current := queue[index]
+
+		tags := a.structTags(current.typ)
+		for i := 0; i < current.typ.NumField(); i++ {
+			field := current.typ.Field(i)
+			if !field.IsExported() && !(a.config.PromoteUnexportedEmbedded && isEmbeddedStruct(field)) {
+				continue
+			}
+
+			actualName, opts := tags[i].name, tags[i].opts
+			if opts.Skip {
+				continue
+			}
+
+			path := current.path + "." + field.Name
+			if field.Anonymous || opts.Squash {
+				if fieldType := indirectType(field.Type); a.squashable(field, opts) {
+					if !embeds(index, fieldType) {
+						queue = append(queue, level{typ: fieldType, depth: current.depth + 1, path: path, parent: index})
+					}
+					continue
+				}
+			}
+
+			def, exist := definitions[actualName]
+			if !exist {
+				definitions[actualName] = &definition{depth: current.depth, paths: []string{path}}
+			} else if def.depth == current.depth {
+				def.paths = append(def.paths, path)
+			}
+		}
+	}
+
+	collisions := make([]string, 0)
+	for name, def := range definitions {
+		if len(def.paths) > 1 {
+			collisions = append(collisions, fmt.Sprintf("'%s' (%s)", name, strings.Join(def.paths, ", ")))
+		}
+	}
+
+	if len(collisions) == 0 {
+		return nil
+	}
+
+	sort.Strings(collisions)
+	return fmt.Errorf("'%s' ambiguous squashed key(s): %s", key.String(), strings.Join(collisions, "; "))
+}
+
+// squashCollisions applies the SquashCollisions policy to the struct
+// type typ.
+func (a *assigner) squashCollisions(typ reflect.Type, key metaKey) error {
+	if a.config.SquashCollisions != SquashCollisionError {
+		return nil
+	}
+	return a.checkSquashCollisions(typ, key)
+}
+
+// squashable reports whether the embedded or squash tagged field is
+// squashed according to the Squash mode and its tag. Only structs are
+// squashed; exported embedded structs without exported fields, such as
+// time.Time, are opaque values kept as regular fields. Unexported embedded
+// structs can't be nested and are always squashed.
+func (a *assigner) squashable(field reflect.StructField, opts TagOptions) bool {
+	fieldType := indirectType(field.Type)
+	if fieldType.Kind() != reflect.Struct {
+		return false
+	}
+
+	switch {
+	case !field.IsExported():
+		return true
+	case opts.NoSquash:
+		return false
+	case opts.Squash:
+		return true
+	case a.config.Squash == SquashTagged:
+		return false
+	}
+	return hasExportedFields(fieldType)
+}
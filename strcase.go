@@ -1,6 +1,23 @@
 package object
 
-import "strings"
+import (
+	"reflect"
+	"strings"
+)
+
+// DefaultInitialisms are common initialisms, for AssignConfig.Initialisms.
+var DefaultInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP",
+	"HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA",
+	"SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID",
+	"URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+// ToLowerCamel converts a struct field name to the default map key,
+// e.g. "UserID" becomes "userId".
+func ToLowerCamel(s string) string {
+	return toLowerCamel(s)
+}
 
 func toLowerCamel(s string) string {
 	return toCamelInitCase(s, false)
@@ -48,3 +65,90 @@ func toCamelInitCase(s string, initCase bool) string {
 	}
 	return n.String()
 }
+
+// convertName converts the struct field name to its map key with the
+// Converter, or with toLowerCamelInitialisms when Initialisms are set and
+// the Converter is the default one.
+func (a *assigner) convertName(name string) string {
+	if len(a.config.Initialisms) > 0 && isDefaultConverter(a.config.Converter) {
+		return toLowerCamelInitialisms(name, a.config.Initialisms)
+	}
+	return a.config.Converter(name)
+}
+
+// isDefaultConverter reports whether converter is the default lowerCamel
+// Converter.
+func isDefaultConverter(converter func(string) string) bool {
+	return reflect.ValueOf(converter).Pointer() == reflect.ValueOf(toLowerCamel).Pointer()
+}
+
+// toLowerCamelInitialisms converts a string to lowerCamel case, writing the
+// words listed in initialisms in upper case, except for the first word,
+// e.g. "UserID" and "user_id" become "userID" and "URLPath" "urlPath".
+func toLowerCamelInitialisms(s string, initialisms []string) string {
+	n := strings.Builder{}
+	n.Grow(len(s))
+	for i, word := range splitWords(strings.TrimSpace(s)) {
+		switch {
+		case i == 0:
+			n.WriteString(strings.ToLower(word))
+		case isInitialism(word, initialisms):
+			n.WriteString(strings.ToUpper(word))
+		default:
+			n.WriteString(strings.ToUpper(word[:1]))
+			n.WriteString(strings.ToLower(word[1:]))
+		}
+	}
+	return n.String()
+}
+
+func isInitialism(word string, initialisms []string) bool {
+	for _, initialism := range initialisms {
+		if strings.EqualFold(word, initialism) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitWords splits s into words at separators, at lower to upper case
+// transitions, before the last letter of a run of upper case letters
+// followed by a lower case one ("HTTPServer" is "HTTP" and "Server") and
+// after digits.
+func splitWords(s string) []string {
+	words := make([]string, 0, 4)
+	start := -1
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !isWordByte(c) {
+			if start >= 0 {
+				words = append(words, s[start:i])
+				start = -1
+			}
+			continue
+		}
+
+		if start >= 0 && i > start {
+			prev := s[i-1]
+			isUpper := c >= 'A' && c <= 'Z'
+			boundary := isUpper && prev >= 'a' && prev <= 'z' ||
+				isUpper && prev >= 'A' && prev <= 'Z' && i+1 < len(s) && s[i+1] >= 'a' && s[i+1] <= 'z' ||
+				!(c >= '0' && c <= '9') && prev >= '0' && prev <= '9'
+			if boundary {
+				words = append(words, s[start:i])
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,64 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// isStringOptionKind reports whether the ",string" tag option applies to
+// values of kind, like encoding/json: booleans and numbers.
+func isStringOptionKind(kind reflect.Kind) bool {
+	return isBool(kind) || isInt(kind) || isUint(kind) || isFloat(kind)
+}
+
+// parseStringOption parses the string sourceVal assigned to a field of
+// type fieldType tagged with the ",string" option into a value of the
+// kind of the field. It reports false when the option doesn't apply.
+func parseStringOption(fieldType reflect.Type, targetKey metaKey, sourceVal reflect.Value) (reflect.Value, bool, error) {
+	sourceVal = indirectValue(sourceVal)
+	elemType := indirectType(fieldType)
+	if !sourceVal.IsValid() || !isString(sourceVal.Kind()) || !isStringOptionKind(elemType.Kind()) {
+		return sourceVal, false, nil
+	}
+
+	str := sourceVal.String()
+	var value any
+	var err error
+	switch kind := elemType.Kind(); {
+	case isBool(kind):
+		value, err = strconv.ParseBool(str)
+	case isInt(kind):
+		value, err = strconv.ParseInt(str, 10, elemType.Bits())
+	case isUint(kind):
+		value, err = strconv.ParseUint(str, 10, elemType.Bits())
+	default:
+		value, err = strconv.ParseFloat(str, elemType.Bits())
+	}
+	if err != nil {
+		return sourceVal, true, fmt.Errorf("'%s' cannot parse '%s' as %s: %w", targetKey.String(), str, elemType.Kind(), err)
+	}
+	return reflect.ValueOf(value), true, nil
+}
+
+// formatStringOption formats the boolean or number val of a field tagged
+// with the ",string" option as a string. It reports false for other
+// values and nil pointers.
+func formatStringOption(val reflect.Value) (reflect.Value, bool) {
+	val = indirectValue(val)
+	if !val.IsValid() {
+		return val, false
+	}
+
+	switch kind := val.Kind(); {
+	case isBool(kind):
+		return reflect.ValueOf(strconv.FormatBool(val.Bool())), true
+	case isInt(kind):
+		return reflect.ValueOf(strconv.FormatInt(val.Int(), 10)), true
+	case isUint(kind):
+		return reflect.ValueOf(strconv.FormatUint(val.Uint(), 10)), true
+	case isFloat(kind):
+		return reflect.ValueOf(strconv.FormatFloat(val.Float(), 'f', -1, val.Type().Bits())), true
+	}
+	return val, false
+}
@@ -0,0 +1,52 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssign_StringOption(t *testing.T) {
+	t.Parallel()
+
+	type Row struct {
+		N    int     `json:"n,string"`
+		B    bool    `json:"b,string"`
+		F    float64 `json:"f,string"`
+		P    *int    `json:"p,string"`
+		Name string  `json:"name,string"`
+	}
+
+	var row Row
+	err := Assign(&row, map[string]any{"n": "12", "b": "true", "f": "1.5", "p": "7", "name": "x"})
+	if err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if row.N != 12 || !row.B || row.F != 1.5 || row.P == nil || *row.P != 7 || row.Name != "x" {
+		t.Fatalf("Assign() = %+v", row)
+	}
+
+	// Non string input is still assigned as usual.
+	row = Row{}
+	if err := Assign(&row, map[string]any{"n": 3}); err != nil || row.N != 3 {
+		t.Fatalf("Assign() = %+v, %v", row, err)
+	}
+
+	if err := Assign(&row, map[string]any{"n": "twelve"}); err == nil {
+		t.Fatalf("Assign() expected error for invalid string")
+	}
+
+	p := 7
+	out := map[string]any{}
+	if err := Assign(&out, Row{N: 12, B: true, F: 1.5, P: &p, Name: "x"}); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	want := map[string]any{"n": "12", "b": "true", "f": "1.5", "p": "7", "name": "x"}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("Assign() = %#v, want %#v", out, want)
+	}
+
+	_, opts := ParseTag(reflect.StructField{Name: "N", Tag: `json:"n,string"`})
+	if opts != (TagOptions{String: true}) {
+		t.Fatalf("ParseTag() = %+v", opts)
+	}
+}
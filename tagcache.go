@@ -0,0 +1,72 @@
+package object
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// tagCache holds the parsed tags of struct types, shared by all assigners
+// using the default Converter so that per call configurations don't start
+// from an empty cache. It maps a tagCacheKey to a []parsedTag. Other
+// Converters can't be told apart reliably, closures of the same function
+// literal share their code, so their tags are cached by the assigner.
+var tagCache sync.Map
+
+// tagCacheKey identifies a struct type and the configuration fields that
+// affect tag parsing.
+type tagCacheKey struct {
+	typ                 reflect.Type
+	tagName             string
+	tagNames            string
+	initialisms         string
+	unnamedTag          UnnamedTagPolicy
+	includeIgnoreFields bool
+}
+
+// parsedTag is the parsed tag of a struct field.
+type parsedTag struct {
+	name string
+	opts TagOptions
+}
+
+// structTags returns the parsed tags of the fields of the struct type typ,
+// indexed like the fields.
+func (a *assigner) structTags(typ reflect.Type) []parsedTag {
+	if !isDefaultConverter(a.config.Converter) {
+		if cached, ok := a.tags.Load(typ); ok {
+			return cached.([]parsedTag)
+		}
+		cached, _ := a.tags.LoadOrStore(typ, a.parseTags(typ))
+		return cached.([]parsedTag)
+	}
+
+	key := tagCacheKey{
+		typ:                 typ,
+		tagName:             a.config.TagName,
+		unnamedTag:          a.config.UnnamedTag,
+		includeIgnoreFields: a.config.IncludeIgnoreFields,
+	}
+	if len(a.config.TagNames) > 0 {
+		key.tagNames = strings.Join(a.config.TagNames, ",")
+	}
+	if len(a.config.Initialisms) > 0 {
+		key.initialisms = strings.Join(a.config.Initialisms, ",")
+	}
+
+	if cached, ok := tagCache.Load(key); ok {
+		return cached.([]parsedTag)
+	}
+
+	cached, _ := tagCache.LoadOrStore(key, a.parseTags(typ))
+	return cached.([]parsedTag)
+}
+
+// parseTags parses the tags of the fields of the struct type typ.
+func (a *assigner) parseTags(typ reflect.Type) []parsedTag {
+	tags := make([]parsedTag, typ.NumField())
+	for i := range tags {
+		tags[i].name, tags[i].opts = a.parseTag(typ.Field(i))
+	}
+	return tags
+}
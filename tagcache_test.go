@@ -0,0 +1,128 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAssign_TagCachePerConfig(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		UserName string `yaml:"user"`
+		Age      int
+	}
+
+	input := map[string]any{"user": "a", "userName": "b", "USERNAME": "c", "age": 1, "AGE": 2}
+
+	upper := func(c *AssignConfig) {
+		c.Converter = strings.ToUpper
+	}
+	yaml := func(c *AssignConfig) {
+		c.TagName = "yaml"
+	}
+
+	cases := []struct {
+		configs  []func(c *AssignConfig)
+		expected Target
+	}{
+		{nil, Target{UserName: "b", Age: 1}},
+		{[]func(c *AssignConfig){upper}, Target{UserName: "c", Age: 2}},
+		{[]func(c *AssignConfig){yaml}, Target{UserName: "a", Age: 1}},
+		{[]func(c *AssignConfig){yaml, upper}, Target{UserName: "a", Age: 2}},
+	}
+
+	// Each configuration keeps its own key names across repeated calls
+	for i := 0; i < 2; i++ {
+		for _, tc := range cases {
+			var result Target
+			if err := Assign(&result, input, tc.configs...); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if result != tc.expected {
+				t.Fatalf("expected %#v, got %#v", tc.expected, result)
+			}
+		}
+	}
+
+	key := tagCacheKey{
+		typ:     reflect.TypeOf(Target{}),
+		tagName: "yaml",
+	}
+	if _, ok := tagCache.Load(key); !ok {
+		t.Fatal("expected the tags of the per call configuration to be cached")
+	}
+}
+
+func TestAssign_TagCacheClosureConverters(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Name string
+	}
+
+	prefixed := func(prefix string) func(string) string {
+		return func(name string) string {
+			return prefix + name
+		}
+	}
+
+	for _, prefix := range []string{"a_", "b_", "a_"} {
+		result := map[string]any{}
+		if err := Assign(&result, Target{Name: "x"}, func(c *AssignConfig) {
+			c.Converter = prefixed(prefix)
+		}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if _, ok := result[prefix+"Name"]; !ok || len(result) != 1 {
+			t.Fatalf("expected key %sName, got %#v", prefix, result)
+		}
+	}
+}
+
+func TestAssigner_TagCacheConcurrent(t *testing.T) {
+	t.Parallel()
+
+	type Inner struct {
+		Value int
+	}
+	type Target struct {
+		Name  string
+		Inner Inner
+	}
+
+	assigner := NewAssigner(func(c *AssignConfig) {
+		c.Converter = strings.ToUpper
+	})
+	input := map[string]any{"NAME": "x", "INNER": map[string]any{"VALUE": 1}}
+	expected := Target{Name: "x", Inner: Inner{Value: 1}}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				var result Target
+				if err := assigner.Assign(&result, input); err != nil {
+					errs <- err
+					return
+				}
+				if result != expected {
+					errs <- fmt.Errorf("expected %#v, got %#v", expected, result)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,31 @@
+package object
+
+import (
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// formatTime formats a time.Time, or a non nil *time.Time, val with the
+// TimeLayout. Without TimeLayout, val is only formatted, as RFC 3339, when
+// toString is true. It reports false when val is not formatted.
+func (a *assigner) formatTime(val reflect.Value, toString bool) (reflect.Value, bool) {
+	if val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	if !val.IsValid() || val.Type() != timeType {
+		return val, false
+	}
+
+	layout := a.config.TimeLayout
+	if layout == "" {
+		if !toString {
+			return val, false
+		}
+		layout = time.RFC3339
+	}
+
+	t := val.Interface().(time.Time)
+	return reflect.ValueOf(t.Format(layout)), true
+}
@@ -0,0 +1,74 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAssign_TimeToMap(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	type Event struct {
+		time.Time
+		Name    string     `json:"name"`
+		Created time.Time  `json:"created"`
+		Updated *time.Time `json:"updated"`
+	}
+
+	input := Event{Time: at, Name: "deploy", Created: at, Updated: &at}
+
+	// Times are kept as is by default, embedded ones included
+	var verbatim map[string]any
+	if err := Assign(&verbatim, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if verbatim["time"] != at || verbatim["created"] != at || verbatim["updated"] != &at {
+		t.Fatalf("bad verbatim: %#v", verbatim)
+	}
+
+	// Maps of strings get RFC 3339 strings
+	var strs map[string]string
+	if err := Assign(&strs, Event{Time: at, Name: "deploy", Created: at}, func(c *AssignConfig) {
+		c.NilPointers = NilPointerOmit
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]string{"time": "2024-03-01T12:30:00Z", "name": "deploy", "created": "2024-03-01T12:30:00Z"}
+	if !reflect.DeepEqual(strs, expected) {
+		t.Fatalf("bad strings: %#v", strs)
+	}
+
+	// TimeLayout formats every time, at any depth with DeepInterfaceMaps
+	type Log struct {
+		Created time.Time  `json:"created"`
+		Updated *time.Time `json:"updated"`
+	}
+
+	type Wrapper struct {
+		Logs []Log `json:"logs"`
+	}
+
+	var deep map[string]any
+	if err := Assign(&deep, Wrapper{Logs: []Log{{Created: at, Updated: &at}}}, func(c *AssignConfig) {
+		c.TimeLayout = "2006-01-02"
+		c.DeepInterfaceMaps = true
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	log := deep["logs"].([]any)[0].(map[string]any)
+	if log["created"] != "2024-03-01" || log["updated"] != "2024-03-01" {
+		t.Fatalf("bad deep: %#v", log)
+	}
+
+	// Round trip through a map
+	var back Event
+	if err := Assign(&back, verbatim); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !back.Time.Equal(at) || !back.Created.Equal(at) || back.Name != "deploy" {
+		t.Fatalf("bad round trip: %#v", back)
+	}
+}
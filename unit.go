@@ -0,0 +1,130 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// durationUnits are the units accepted by the "unit=" tag option.
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+func parseUnit(name string, key metaKey) (time.Duration, error) {
+	unit, ok := durationUnits[name]
+	if !ok {
+		return 0, fmt.Errorf("'%s' unknown unit '%s'", key.String(), name)
+	}
+	return unit, nil
+}
+
+// assignField assigns sourceVal to a struct field, applying the options
+// of its tag.
+func (a *assigner) assignField(field fieldInfo, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey) error {
+	if field.String {
+		parsed, ok, err := parseStringOption(field.fieldVal.Type(), targetKey, sourceVal)
+		if err != nil {
+			return err
+		}
+		if ok {
+			sourceVal = parsed
+		}
+	}
+	if field.Unit != "" {
+		return a.assignUnit(field.fieldVal, targetKey, sourceVal, sourceKey, field.Unit)
+	}
+	if field.Scale != "" {
+		return a.assignScale(field, targetKey, sourceVal, sourceKey)
+	}
+	return a.assignContext(field.fieldVal, targetKey, sourceVal, sourceKey, &field)
+}
+
+// assignUnit assigns a field tagged with a unit: numbers assigned to
+// durations are multiplied by the unit and durations assigned to numbers
+// are divided by it. Anything else is assigned as usual.
+func (a *assigner) assignUnit(targetVal reflect.Value, targetKey metaKey, sourceVal reflect.Value, sourceKey metaKey, name string) error {
+	unit, err := parseUnit(name, targetKey)
+	if err != nil {
+		return err
+	}
+
+	source := indirectValue(sourceVal)
+	if !source.IsValid() {
+		return a.assign(targetVal, targetKey, sourceVal, sourceKey)
+	}
+
+	if targetVal.Type() == durationType && source.Type() != durationType {
+		var d time.Duration
+		switch {
+		case isInt(source.Kind()):
+			d = time.Duration(source.Int()) * unit
+		case isUint(source.Kind()):
+			d = time.Duration(source.Uint()) * unit
+		case isFloat(source.Kind()):
+			d = time.Duration(source.Float() * float64(unit))
+		case isJsonNumber(source.Type()), isString(source.Kind()) && (a.config.WeaklyTypedInput || a.config.NumberStrings):
+			str := source.String()
+			if !isNumberText(str) {
+				// Strings with units, such as "1.5s", are parsed as is
+				parsed, err := time.ParseDuration(str)
+				if err != nil {
+					return fmt.Errorf("cannot parse '%s' as duration: %s", targetKey.String(), err)
+				}
+				targetVal.SetInt(int64(parsed))
+				a.addMetaKey(targetKey)
+				return nil
+			}
+			f, err := strconv.ParseFloat(str, 64)
+			if err != nil {
+				return fmt.Errorf("cannot parse '%s' as %s duration: %s", targetKey.String(), name, err)
+			}
+			d = time.Duration(f * float64(unit))
+		default:
+			return a.assign(targetVal, targetKey, sourceVal, sourceKey)
+		}
+
+		targetVal.SetInt(int64(d))
+		a.addMetaKey(targetKey)
+		return nil
+	}
+
+	if targetVal.Type() != durationType && source.Type() == durationType {
+		value, err := durationInUnit(source, name, sourceKey)
+		if err != nil {
+			return err
+		}
+		return a.assign(targetVal, targetKey, value, sourceKey)
+	}
+
+	return a.assign(targetVal, targetKey, sourceVal, sourceKey)
+}
+
+// durationInUnit converts a duration into a number of unit: an int64 when
+// the duration is a whole number of units, a float64 otherwise. Other
+// values are returned as is.
+func durationInUnit(val reflect.Value, name string, key metaKey) (reflect.Value, error) {
+	if !val.IsValid() || val.Type() != durationType {
+		return val, nil
+	}
+
+	unit, err := parseUnit(name, key)
+	if err != nil {
+		return val, err
+	}
+
+	d := time.Duration(val.Int())
+	if d%unit == 0 {
+		return reflect.ValueOf(int64(d / unit)), nil
+	}
+	return reflect.ValueOf(float64(d) / float64(unit)), nil
+}
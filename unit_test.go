@@ -0,0 +1,85 @@
+package object
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssign_Unit(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Timeout  time.Duration `json:"timeout,unit=ms"`
+		Interval time.Duration `json:"interval,unit=s"`
+		Delay    time.Duration `json:"delay,unit=ms"`
+		Raw      time.Duration `json:"raw"`
+		TTL      int           `json:"ttl,unit=s"`
+		Ratio    float64       `json:"ratio,unit=m"`
+	}
+
+	input := map[string]any{
+		"timeout":  1500,
+		"interval": 2.5,
+		"delay":    json.Number("250"),
+		"raw":      100,
+		"ttl":      90 * time.Second,
+		"ratio":    90 * time.Second,
+	}
+
+	var result Target
+	if err := Assign(&result, input); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Target{
+		Timeout:  1500 * time.Millisecond,
+		Interval: 2500 * time.Millisecond,
+		Delay:    250 * time.Millisecond,
+		Raw:      100,
+		TTL:      90,
+		Ratio:    1.5,
+	}
+	if result != expected {
+		t.Fatalf("expected %#v, got %#v", expected, result)
+	}
+
+	weak := func(c *AssignConfig) {
+		c.WeaklyTypedInput = true
+	}
+	result = Target{}
+	if err := Assign(&result, map[string]any{"timeout": "20", "interval": "1m"}, weak); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Timeout != 20*time.Millisecond || result.Interval != time.Minute {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	actual := map[string]any{}
+	if err := Assign(&actual, expected); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual["timeout"] != int64(1500) || actual["interval"] != 2.5 || actual["raw"] != time.Duration(100) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestAssign_UnitErrors(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Timeout time.Duration `json:"timeout,unit=days"`
+	}
+
+	var result Target
+	err := Assign(&result, map[string]any{"timeout": 1})
+	if err == nil || !strings.Contains(err.Error(), "'Timeout' unknown unit 'days'") {
+		t.Fatalf("expected an unknown unit error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(durationUnits["µs"], durationUnits["us"]) {
+		t.Fatalf("µs and us must be aliases")
+	}
+}
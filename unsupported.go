@@ -0,0 +1,40 @@
+package object
+
+import "reflect"
+
+// UnsupportedSourcePolicy selects how source values of kinds that can't be
+// converted, such as channels and functions, are handled.
+type UnsupportedSourcePolicy int
+
+const (
+	// UnsupportedSourceError reports unsupported source values as an
+	// unconvertible type error, unless the target can hold them as is.
+	// This is the default.
+	UnsupportedSourceError UnsupportedSourcePolicy = iota
+
+	// UnsupportedSourceSkip skips unsupported source values, leaving the
+	// target untouched, and records them in Metadata.Unused. Struct
+	// fields and map entries holding them are left out of map targets.
+	UnsupportedSourceSkip
+)
+
+// skipUnsupported reports whether val must be skipped according to the
+// UnsupportedSources policy.
+func (a *assigner) skipUnsupported(val reflect.Value) bool {
+	if a.config.UnsupportedSources != UnsupportedSourceSkip {
+		return false
+	}
+
+	if val.IsValid() && val.Kind() == reflect.Interface {
+		val = val.Elem()
+	}
+	if !val.IsValid() {
+		return false
+	}
+
+	switch val.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package object
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestAssign_UnsupportedSources(t *testing.T) {
+	t.Parallel()
+
+	type Telemetry struct {
+		Name   string      `json:"name"`
+		Count  int         `json:"count"`
+		Done   chan bool   `json:"done"`
+		OnTick func()      `json:"onTick"`
+		Extra  interface{} `json:"extra"`
+	}
+
+	input := Telemetry{
+		Name:   "cpu",
+		Count:  3,
+		Done:   make(chan bool),
+		OnTick: func() {},
+		Extra:  make(chan int),
+	}
+
+	type Target struct {
+		Name   string `json:"name"`
+		Count  int    `json:"count"`
+		Done   string `json:"done"`
+		OnTick string `json:"onTick"`
+		Extra  string `json:"extra"`
+	}
+
+	// By default they are unconvertible
+	var failed Target
+	if err := Assign(&failed, input); err == nil || !strings.Contains(err.Error(), "unconvertible type 'chan bool'") {
+		t.Fatalf("expected unconvertible error, got %v", err)
+	}
+
+	var md Metadata
+	skip := func(c *AssignConfig) {
+		c.UnsupportedSources = UnsupportedSourceSkip
+		c.Metadata = &md
+	}
+
+	var result Target
+	if err := Assign(&result, input, skip); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != (Target{Name: "cpu", Count: 3}) {
+		t.Fatalf("bad: %#v", result)
+	}
+	sort.Strings(md.Unused)
+	if !reflect.DeepEqual(md.Unused, []string{"Done", "Extra", "OnTick"}) {
+		t.Fatalf("bad unused: %#v", md.Unused)
+	}
+
+	// Map targets leave them out
+	md = Metadata{}
+	var m map[string]any
+	if err := Assign(&m, input, skip); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(m) != 2 || m["name"] != "cpu" || m["count"] != 3 {
+		t.Fatalf("bad: %#v", m)
+	}
+
+	var ms map[string]string
+	if err := Assign(&ms, map[string]any{"a": "x", "b": make(chan int)}, skip); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(ms) != 1 || ms["a"] != "x" {
+		t.Fatalf("bad: %#v", ms)
+	}
+}
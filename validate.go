@@ -0,0 +1,147 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidateTagName is the struct tag holding the validation rules checked
+// when AssignConfig.Validate is set, e.g. `validate:"required,min=1"`.
+//
+// The supported rules are:
+//   - required: the value isn't the zero value of its type.
+//   - min=N, max=N: numbers are at least, or at most, N. Strings (counted
+//     in characters), slices, arrays and maps have at least, or at most, N
+//     elements.
+//   - len=N: strings, slices, arrays and maps have exactly N elements.
+//   - oneof=a b c: the value, formatted, is one of the space separated
+//     values.
+//
+// Other rules are ignored, so the tag can be shared with other validators.
+// Rules other than required are not checked on nil pointers. The fields of
+// nested structs are checked when the struct is assigned.
+const ValidateTagName = "validate"
+
+// ValidationError is returned for a field whose value breaks a rule of its
+// validate tag, see ValidateTagName.
+type ValidationError struct {
+	// Path is the full path of the field.
+	Path string
+
+	// Rule is the rule that failed, e.g. "min=1".
+	Rule string
+
+	// Value is the value of the field.
+	Value any
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("'%s' failed validation '%s': %v", e.Path, e.Rule, e.Value)
+}
+
+// markFailed records that the field named name failed to be assigned, so
+// that it isn't validated.
+func markFailed(failed map[string]bool, name string) map[string]bool {
+	if failed == nil {
+		failed = make(map[string]bool)
+	}
+	failed[name] = true
+	return failed
+}
+
+// validateFields appends to errors the violations of the validate tags of
+// fields, except the fields that failed to be assigned.
+func (a *assigner) validateFields(fields map[string]fieldInfo, targetKey metaKey, failed map[string]bool, errors []error) []error {
+	for name, field := range fields {
+		tag, ok := field.field.Tag.Lookup(ValidateTagName)
+		if !ok || failed[name] {
+			continue
+		}
+
+		if err := validateValue(field.fieldVal, targetKey.newChild(reflect.Struct, field.displayName), tag); err != nil {
+			errors = append(errors, err)
+			if a.config.FailFast {
+				break
+			}
+		}
+	}
+	return errors
+}
+
+// validateValue checks val against the comma separated rules of tag and
+// returns the first violation.
+func validateValue(val reflect.Value, key metaKey, tag string) error {
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		if name == "required" {
+			if isZeroValue(val) {
+				return &ValidationError{Path: key.String(), Rule: rule, Value: val.Interface()}
+			}
+			continue
+		}
+
+		value := indirectValue(val)
+		if !value.IsValid() {
+			continue
+		}
+
+		ok := true
+		switch name {
+		case "min", "max", "len":
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return fmt.Errorf("'%s' invalid validation rule '%s': %w", key.String(), rule, err)
+			}
+			size, sized := validationSize(value, name == "len")
+			switch {
+			case !sized:
+				return fmt.Errorf("'%s' validation rule '%s' doesn't apply to '%s'", key.String(), rule, value.Type())
+			case name == "min":
+				ok = size >= limit
+			case name == "max":
+				ok = size <= limit
+			default:
+				ok = size == limit
+			}
+		case "oneof":
+			ok = false
+			formatted := fmt.Sprint(value.Interface())
+			for _, option := range strings.Fields(arg) {
+				if option == formatted {
+					ok = true
+					break
+				}
+			}
+		}
+
+		if !ok {
+			return &ValidationError{Path: key.String(), Rule: rule, Value: value.Interface()}
+		}
+	}
+	return nil
+}
+
+// validationSize returns the number compared by the min, max and len rules:
+// the value of numbers, unless length is set, and the length of strings,
+// slices, arrays and maps.
+func validationSize(val reflect.Value, length bool) (float64, bool) {
+	switch kind := val.Kind(); {
+	case kind == reflect.String:
+		return float64(utf8.RuneCountInString(val.String())), true
+	case isArraySlice(kind) || kind == reflect.Map:
+		return float64(val.Len()), true
+	case length:
+		return 0, false
+	case isInt(kind):
+		return float64(val.Int()), true
+	case isUint(kind):
+		return float64(val.Uint()), true
+	case isFloat(kind):
+		return val.Float(), true
+	}
+	return 0, false
+}
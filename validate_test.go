@@ -0,0 +1,92 @@
+package object
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+func TestAssign_Validate(t *testing.T) {
+	t.Parallel()
+
+	type Address struct {
+		City string `json:"city" validate:"required"`
+	}
+	type User struct {
+		Name    string         `json:"name" validate:"required,min=2,max=5"`
+		Age     int            `json:"age" validate:"min=18"`
+		Role    string         `json:"role" validate:"oneof=admin user"`
+		Tags    []string       `json:"tags" validate:"len=2"`
+		Email   *string        `json:"email" validate:"min=3,email"`
+		Address Address        `json:"address"`
+		Extra   map[string]int `json:"extra" validate:"max=1"`
+	}
+
+	validate := func(c *AssignConfig) {
+		c.Validate = true
+	}
+
+	var valid User
+	err := Assign(&valid, map[string]any{
+		"name":    "ann",
+		"age":     "30",
+		"role":    "user",
+		"tags":    []string{"a", "b"},
+		"address": map[string]any{"city": "x"},
+	}, validate, func(c *AssignConfig) {
+		c.WeaklyTypedInput = true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var invalid User
+	err = Assign(&invalid, map[string]any{
+		"name":    "a",
+		"age":     "x",
+		"role":    "root",
+		"tags":    []string{"a"},
+		"extra":   map[string]int{"a": 1, "b": 2},
+		"address": map[string]any{},
+	}, validate)
+
+	var derr *Error
+	if !errors.As(err, &derr) {
+		t.Fatalf("expected an *Error, got %v", err)
+	}
+
+	var rules []string
+	for _, field := range derr.FieldErrors() {
+		var verr *ValidationError
+		if errors.As(field.Err, &verr) {
+			rules = append(rules, verr.Path+" "+verr.Rule)
+		}
+	}
+	sort.Strings(rules)
+
+	// Age failed to be assigned, so it isn't validated
+	expected := []string{
+		"Address.City required",
+		"Extra max=1",
+		"Name min=2",
+		"Role oneof=admin user",
+		"Tags len=2",
+	}
+	if len(rules) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, rules)
+	}
+	for i := range rules {
+		if rules[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, rules)
+		}
+	}
+	if len(derr.Errors) != len(expected)+1 {
+		t.Fatalf("expected the age error alongside the violations, got %s", err)
+	}
+
+	// Without Validate the tags are ignored
+	var unchecked User
+	if err := Assign(&unchecked, map[string]any{"name": "a"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,44 @@
+package object
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// whenInMap reports whether the value of key in the source map sourceVal,
+// the condition of a "when=" option, is true.
+func (a *assigner) whenInMap(sourceVal reflect.Value, key string) bool {
+	keyVal := reflect.New(sourceVal.Type().Key()).Elem()
+	if err := a.assignKey(keyVal, reflect.ValueOf(key)); err != nil {
+		return false
+	}
+	return isTrue(sourceVal.MapIndex(keyVal))
+}
+
+// whenInStruct reports whether the field named key of the source struct
+// sourceVal, the condition of a "when=" option, is true.
+func (a *assigner) whenInStruct(sourceVal reflect.Value, key string) bool {
+	for _, field := range a.flattenStruct(sourceVal, false) {
+		if field.actualName == key {
+			return isTrue(field.fieldVal)
+		}
+	}
+	return false
+}
+
+// isTrue reports whether val holds true, or a string parsed as true.
+func isTrue(val reflect.Value) bool {
+	val = indirectValue(val)
+	if !val.IsValid() {
+		return false
+	}
+
+	switch val.Kind() {
+	case reflect.Bool:
+		return val.Bool()
+	case reflect.String:
+		b, err := strconv.ParseBool(val.String())
+		return err == nil && b
+	}
+	return false
+}
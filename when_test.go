@@ -0,0 +1,73 @@
+package object
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestAssign_When(t *testing.T) {
+	t.Parallel()
+
+	type TLS struct {
+		Enabled bool   `object:"tls_enabled"`
+		Cert    string `object:"tls_cert,when=tls_enabled"`
+		Port    int    `object:"port"`
+	}
+
+	config := func(md *Metadata) func(c *AssignConfig) {
+		return func(c *AssignConfig) {
+			c.TagName = "object"
+			c.Metadata = md
+		}
+	}
+
+	var md Metadata
+	var disabled TLS
+	if err := Assign(&disabled, map[string]any{
+		"tls_enabled": false,
+		"tls_cert":    "/etc/cert.pem",
+		"port":        443,
+	}, config(&md)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if disabled != (TLS{Port: 443}) {
+		t.Fatalf("bad: %#v", disabled)
+	}
+	if !reflect.DeepEqual(md.Unset, []string{"Cert"}) || !reflect.DeepEqual(md.Unused, []string{"tls_cert"}) {
+		t.Fatalf("bad metadata: %#v", md)
+	}
+
+	var enabled TLS
+	if err := Assign(&enabled, map[string]any{
+		"tls_enabled": true,
+		"tls_cert":    "/etc/cert.pem",
+	}, config(nil)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if enabled.Cert != "/etc/cert.pem" || !enabled.Enabled {
+		t.Fatalf("bad: %#v", enabled)
+	}
+
+	// Struct sources are checked too, in both directions
+	var copied TLS
+	if err := Assign(&copied, TLS{Cert: "x", Port: 1}, config(nil)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if copied != (TLS{Port: 1}) {
+		t.Fatalf("bad: %#v", copied)
+	}
+
+	var m map[string]any
+	if err := Assign(&m, TLS{Cert: "x", Port: 1}, config(nil)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"port", "tls_enabled"}) {
+		t.Fatalf("bad: %#v", m)
+	}
+}
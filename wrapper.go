@@ -0,0 +1,66 @@
+package object
+
+import (
+	"reflect"
+)
+
+// isWrapperType reports whether typ is a wrapper type, such as the
+// wrapperspb types of protobuf: a struct whose only exported field is a
+// scalar or []byte Value field, with a GetValue method.
+func isWrapperType(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+
+	field, ok := typ.FieldByName("Value")
+	if !ok || len(field.Index) != 1 || !isWrappedType(field.Type) {
+		return false
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		if f := typ.Field(i); f.IsExported() && f.Name != "Value" {
+			return false
+		}
+	}
+
+	_, ok = reflect.PointerTo(typ).MethodByName("GetValue")
+	return ok
+}
+
+func isWrappedType(typ reflect.Type) bool {
+	return isScalar(typ.Kind()) || typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+}
+
+// unwrapValue returns the Value field of a non nil wrapper, or of a
+// pointer to one, when Wrappers is enabled.
+func (a *assigner) unwrapValue(val reflect.Value) (reflect.Value, bool) {
+	if !a.config.Wrappers || !val.IsValid() {
+		return val, false
+	}
+
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return val, false
+		}
+		val = val.Elem()
+	}
+
+	if !isWrapperType(val.Type()) {
+		return val, false
+	}
+	return val.FieldByName("Value"), true
+}
+
+// wrappedTarget returns the Value field of a wrapper target assigned a
+// source that isn't a map or struct, when Wrappers is enabled.
+func (a *assigner) wrappedTarget(targetVal reflect.Value, sourceVal reflect.Value) (reflect.Value, bool) {
+	if !a.config.Wrappers || !isWrapperType(targetVal.Type()) {
+		return targetVal, false
+	}
+
+	switch indirectValue(sourceVal).Kind() {
+	case reflect.Map, reflect.Struct:
+		return targetVal, false
+	}
+	return targetVal.FieldByName("Value"), true
+}
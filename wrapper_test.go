@@ -0,0 +1,94 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+// stringValue and int64Value mimic the wrapperspb types of protobuf.
+type stringValue struct {
+	state int
+	Value string
+}
+
+func (x *stringValue) GetValue() string { return x.Value }
+
+type int64Value struct {
+	sizeCache int32
+	Value     int64
+}
+
+func (x *int64Value) GetValue() int64 { return x.Value }
+
+func TestAssign_Wrappers(t *testing.T) {
+	t.Parallel()
+
+	type Request struct {
+		Name  *stringValue
+		Limit *int64Value
+		Plain int64
+	}
+
+	withWrappers := func(c *AssignConfig) {
+		c.Wrappers = true
+	}
+
+	var request Request
+	input := map[string]any{"name": "svc", "limit": 10, "plain": 3}
+	if err := Assign(&request, input, withWrappers); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if request.Name == nil || request.Name.Value != "svc" || request.Limit == nil || request.Limit.Value != 10 {
+		t.Fatalf("bad: %+v", request)
+	}
+
+	// Wrappers are unwrapped to scalars when encoding.
+	var output map[string]any
+	if err := Assign(&output, request, withWrappers); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]any{"name": "svc", "limit": int64(10), "plain": int64(3)}
+	if !reflect.DeepEqual(output, expected) {
+		t.Fatalf("expected %v, got %v", expected, output)
+	}
+
+	// Nil wrappers stay nil.
+	request = Request{}
+	if err := Assign(&request, map[string]any{"name": nil}, withWrappers); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if request.Name != nil {
+		t.Fatalf("expected a nil wrapper, got %+v", request.Name)
+	}
+
+	// Wrappers are unwrapped into scalar fields, scalars wrapped.
+	type Flat struct {
+		Name  string
+		Limit int
+		Plain *int64Value
+	}
+	var flat Flat
+	source := Request{Name: &stringValue{Value: "api"}, Limit: &int64Value{Value: 5}, Plain: 7}
+	if err := Assign(&flat, source, withWrappers); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if flat.Name != "api" || flat.Limit != 5 || flat.Plain == nil || flat.Plain.Value != 7 {
+		t.Fatalf("bad: %+v", flat)
+	}
+
+	// Wrappers held by maps are unwrapped into empty and non empty maps.
+	for _, target := range []map[string]any{{}, {"other": 1}} {
+		if err := Assign(&target, map[string]any{"name": &stringValue{Value: "x"}}, withWrappers); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if target["name"] != "x" {
+			t.Fatalf("expected an unwrapped value, got %#v", target["name"])
+		}
+	}
+
+	// Without the option wrappers are plain structs.
+	request = Request{}
+	if err := Assign(&request, input); err == nil {
+		t.Fatalf("expected an error")
+	}
+}